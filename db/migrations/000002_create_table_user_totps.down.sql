@@ -0,0 +1,3 @@
+drop index if exists uq_user_totps_user_id_not_deleted;
+
+drop table if exists user_totps cascade;
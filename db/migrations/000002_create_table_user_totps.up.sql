@@ -0,0 +1,16 @@
+create table if not exists user_totps (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    secret text not null,
+    is_enabled boolean not null default false,
+    last_used_step bigint not null default 0,
+    confirmed_at timestamp default null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp default null,
+    deleted_at timestamp default null
+);
+
+comment on column user_totps.secret is
+'base32 totp secret encrypted with aes-gcm';
+
+create unique index if not exists uq_user_totps_user_id_not_deleted on user_totps (user_id) where deleted_at is null;
@@ -0,0 +1,4 @@
+drop index if exists idx_user_webauthn_credentials_user_id_not_deleted;
+drop index if exists uq_user_webauthn_credentials_credential_id;
+
+drop table if exists user_webauthn_credentials cascade;
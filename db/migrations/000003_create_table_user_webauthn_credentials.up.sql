@@ -0,0 +1,18 @@
+create table if not exists user_webauthn_credentials (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    credential_id bytea not null,
+    name varchar(255) not null,
+    credential jsonb not null,
+    last_used_at timestamp default null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp default null,
+    deleted_at timestamp default null
+);
+
+comment on column user_webauthn_credentials.credential is
+'serialized webauthn credential (public key, flags, authenticator sign count)';
+
+create index if not exists idx_user_webauthn_credentials_user_id_not_deleted on user_webauthn_credentials (user_id) where deleted_at is null;
+
+create unique index if not exists uq_user_webauthn_credentials_credential_id on user_webauthn_credentials (credential_id) where deleted_at is null;
@@ -0,0 +1,3 @@
+drop index if exists idx_password_history_user_id_created_at;
+
+drop table if exists password_history cascade;
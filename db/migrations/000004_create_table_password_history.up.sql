@@ -0,0 +1,11 @@
+create table if not exists password_history (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    hash_password text not null,
+    created_at timestamp not null default current_timestamp
+);
+
+comment on table password_history is
+'last password hashes of every user, pruned to PASSWORD_HISTORY_SIZE entries';
+
+create index if not exists idx_password_history_user_id_created_at on password_history (user_id, created_at desc);
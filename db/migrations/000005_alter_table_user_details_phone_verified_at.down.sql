@@ -0,0 +1 @@
+alter table user_details drop column if exists phone_verified_at;
@@ -0,0 +1,4 @@
+alter table user_details add column if not exists phone_verified_at timestamp default null;
+
+comment on column user_details.phone_verified_at is
+'set once the phone number is confirmed by an sms code, cleared whenever the number changes';
@@ -0,0 +1,4 @@
+alter table users add column if not exists deactivated_by uuid default null;
+
+comment on column users.deactivated_by is
+'who set is_active to false, the user themselves may reactivate by email, anyone else leaves it to an admin';
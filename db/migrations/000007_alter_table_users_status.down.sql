@@ -0,0 +1,18 @@
+drop table if exists user_status_history;
+
+alter table users add column if not exists is_verified boolean not null default false;
+alter table users add column if not exists is_active boolean not null default true;
+alter table users add column if not exists deactivated_by uuid default null;
+
+update users set
+    is_verified = status <> 0,
+    is_active = status not in (2, 3, 4),
+    deactivated_by = case when status = 3 then status_changed_by else null end;
+
+drop index if exists idx_users_status_not_deleted;
+
+alter table users drop column if exists status;
+alter table users drop column if exists status_reason;
+alter table users drop column if exists suspended_until;
+alter table users drop column if exists status_changed_at;
+alter table users drop column if exists status_changed_by;
@@ -0,0 +1,42 @@
+alter table users add column if not exists status smallint not null default 0;
+alter table users add column if not exists status_reason varchar(255) default null;
+alter table users add column if not exists suspended_until timestamp default null;
+alter table users add column if not exists status_changed_at timestamp default null;
+alter table users add column if not exists status_changed_by uuid default null;
+
+update users set
+    status = case
+        when deleted_at is not null then 5
+        when not is_active then 3
+        when is_verified then 1
+        else 0
+    end,
+    status_changed_at = case when not is_active then updated_at else null end,
+    status_changed_by = case when not is_active then deactivated_by else null end;
+
+alter table users drop column if exists is_verified;
+alter table users drop column if exists is_active;
+alter table users drop column if exists deactivated_by;
+
+create table if not exists user_status_history (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    from_status smallint not null,
+    to_status smallint not null,
+    reason varchar(255) default null,
+    suspended_until timestamp default null,
+    actor_id uuid not null,
+    created_at timestamp not null default current_timestamp
+);
+
+comment on column users.status is
+'0 = pending_verification, 1 = active, 2 = suspended, 3 = deactivated, 4 = pending_deletion, 5 = deleted';
+
+comment on column users.status_changed_by is
+'who made the last status transition, a user deactivating themselves may reactivate by email';
+
+comment on table user_status_history is
+'every account status transition with its actor and reason';
+
+create index if not exists idx_users_status_not_deleted on users (status) where deleted_at is null;
+create index if not exists idx_user_status_history_user_id_created_at on user_status_history (user_id, created_at desc);
@@ -0,0 +1,3 @@
+drop index if exists idx_users_pending_deletion;
+
+alter table users drop column if exists purged_at;
@@ -0,0 +1,6 @@
+alter table users add column if not exists purged_at timestamp default null;
+
+comment on column users.purged_at is
+'when the personal data of a self-deleted account was purged, the anonymized row stays behind as a tombstone';
+
+create index if not exists idx_users_pending_deletion on users (status_changed_at) where status = 4 and deleted_at is null;
@@ -0,0 +1,4 @@
+drop index if exists uq_user_identities_provider_user_id;
+drop index if exists idx_user_identities_user_id_not_deleted;
+
+drop table if exists user_identities cascade;
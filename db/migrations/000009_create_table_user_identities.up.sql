@@ -0,0 +1,21 @@
+create table if not exists user_identities (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    provider varchar(64) not null,
+    provider_user_id varchar(255) not null,
+    email varchar(255) not null default '',
+    last_used_at timestamp default null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp default null,
+    deleted_at timestamp default null
+);
+
+comment on table user_identities is
+'external login identities linked to a user';
+
+comment on column user_identities.email is
+'email the provider reported when the identity was linked, kept for display only';
+
+create index if not exists idx_user_identities_user_id_not_deleted on user_identities (user_id) where deleted_at is null;
+
+create unique index if not exists uq_user_identities_provider_user_id on user_identities (provider, provider_user_id) where deleted_at is null;
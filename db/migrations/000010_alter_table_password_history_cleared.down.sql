@@ -0,0 +1,6 @@
+drop index if exists idx_password_history_user_id_hashed;
+
+delete from password_history where hash_password = '';
+
+comment on table password_history is
+'last password hashes of every user, pruned to PASSWORD_HISTORY_SIZE entries';
@@ -0,0 +1,4 @@
+comment on table password_history is
+'every password change of a user, hashes beyond the last PASSWORD_HISTORY_SIZE entries are cleared and only the time of the change stays';
+
+create index if not exists idx_password_history_user_id_hashed on password_history (user_id, created_at desc) where hash_password <> '';
@@ -0,0 +1,18 @@
+create table if not exists user_audit_events (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    event varchar(64) not null,
+    detail varchar(255) default null,
+    actor_id uuid not null,
+    client_ip varchar(64) not null default '',
+    user_agent text not null default '',
+    created_at timestamp not null default current_timestamp
+);
+
+comment on table user_audit_events is
+'security relevant events of an account, such as sign-ins and changes to its sign-in methods, removed when the account is purged';
+
+comment on column user_audit_events.actor_id is
+'who caused the event, the user themselves or an administrator';
+
+create index if not exists idx_user_audit_events_user_id_created_at on user_audit_events (user_id, created_at desc);
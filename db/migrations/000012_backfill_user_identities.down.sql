@@ -0,0 +1,5 @@
+drop index if exists idx_user_identities_user_id_unclaimed;
+
+delete from user_identities where provider_user_id is null;
+
+alter table user_identities alter column provider_user_id set not null;
@@ -0,0 +1,13 @@
+alter table user_identities alter column provider_user_id drop not null;
+
+comment on column user_identities.provider_user_id is
+'null for an identity backfilled from a google account created before identities were recorded, it is claimed by the first sign-in with a verified matching email';
+
+insert into user_identities (user_id, provider, provider_user_id, email, created_at)
+select u.id, 'google', null, u.email, u.created_at
+from users u
+where u.is_oauth and u.deleted_at is null and not exists (
+    select 1 from user_identities i where i.user_id = u.id and i.deleted_at is null
+);
+
+create index if not exists idx_user_identities_user_id_unclaimed on user_identities (user_id) where provider_user_id is null and deleted_at is null;
@@ -0,0 +1,79 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewAccountInactiveError() *apperror.AppError {
+	msg := constant.AccountInactiveErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
+
+func NewInvalidReactivationTokenError() *apperror.AppError {
+	msg := constant.InvalidReactivationTokenErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewReactivationNotAllowedError() *apperror.AppError {
+	msg := constant.ReactivationNotAllowedErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
+
+func NewAccountSuspendedError(until *time.Time) *apperror.AppError {
+	msg := constant.AccountSuspendedErrorMessage
+	if until != nil {
+		msg = fmt.Sprintf(constant.AccountSuspendedUntilErrorMessage, until.UTC().Format(time.RFC3339))
+	}
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
+
+func NewAccountPendingDeletionError() *apperror.AppError {
+	msg := constant.AccountPendingDeletionErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
+
+func NewInvalidStatusTransitionError(from, to custom_type.UserStatus) *apperror.AppError {
+	msg := fmt.Sprintf(constant.InvalidStatusTransitionErrorMessage, from, to)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewSuspendedUntilPastError() *apperror.AppError {
+	msg := constant.SuspendedUntilPastErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+// NewAccountDeletionEmailError keeps err as the cause, the status change has
+// been committed already and only the email is missing.
+func NewAccountDeletionEmailError(err error) *apperror.AppError {
+	msg := constant.AccountDeletionEmailErrorMessage
+
+	return apperror.NewAppError(err, apperror.DefaultServerErrorCode, msg)
+}
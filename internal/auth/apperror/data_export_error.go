@@ -0,0 +1,24 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewDataExportAlreadyRequestedError() *apperror.AppError {
+	msg := constant.DataExportAlreadyRequestedMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.TooManyRequestsErrorCode, msg)
+}
+
+func NewInvalidDataExportLinkError() *apperror.AppError {
+	msg := constant.InvalidDataExportLinkErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
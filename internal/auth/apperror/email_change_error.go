@@ -0,0 +1,32 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewSameEmailError() *apperror.AppError {
+	msg := constant.SameEmailErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewOauthEmailChangeError() *apperror.AppError {
+	msg := constant.OauthEmailChangeErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
+
+func NewInvalidEmailChangeTokenError() *apperror.AppError {
+	msg := constant.InvalidEmailChangeTokenErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
@@ -0,0 +1,81 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewIdentityNotFoundError() *apperror.AppError {
+	msg := constant.IdentityNotFoundErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.NotFoundErrorCode, msg)
+}
+
+func NewInvalidIdentityIdError() *apperror.AppError {
+	msg := constant.InvalidIdentityId
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewUnsupportedOauthProviderError(provider string) *apperror.AppError {
+	msg := fmt.Sprintf(constant.UnsupportedOauthProviderErrorMessage, provider)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewIdentityAlreadyLinkedError(provider string) *apperror.AppError {
+	msg := fmt.Sprintf(constant.IdentityAlreadyLinkedErrorMessage, provider)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewOauthAccountNotLinkedError(provider string) *apperror.AppError {
+	msg := fmt.Sprintf(constant.OauthAccountNotLinkedErrorMessage, provider)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewInvalidOauthLinkError() *apperror.AppError {
+	msg := constant.InvalidOauthLinkErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewLastLoginMethodError() *apperror.AppError {
+	msg := constant.LastLoginMethodErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewOauthEmailMissingError(provider string) *apperror.AppError {
+	msg := fmt.Sprintf(constant.OauthEmailMissingErrorMessage, provider)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewOauthEmailNotVerifiedError(provider string) *apperror.AppError {
+	msg := fmt.Sprintf(constant.OauthEmailNotVerifiedErrorMessage, provider, provider)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
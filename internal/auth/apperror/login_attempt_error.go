@@ -0,0 +1,25 @@
+package apperror
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewAccountLockedError(retryAfter time.Duration) *apperror.AppError {
+	msg := constant.AccountLockedErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.LockedErrorCode, msg).WithRetryAfter(retryAfter)
+}
+
+func NewTooManyLoginAttemptsError(retryAfter time.Duration) *apperror.AppError {
+	msg := constant.TooManyLoginAttemptsErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.TooManyRequestsErrorCode, msg).WithRetryAfter(retryAfter)
+}
@@ -0,0 +1,16 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewInvalidMagicLinkError() *apperror.AppError {
+	msg := constant.InvalidMagicLinkErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
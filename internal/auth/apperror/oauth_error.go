@@ -0,0 +1,45 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+// OAuthError is rendered as a RFC 6749 error response instead of the default
+// web response so stock OAuth2/OIDC client libraries can understand it.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	StatusCode  int    `json:"-"`
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{
+		Code:        code,
+		Description: description,
+		StatusCode:  http.StatusBadRequest,
+	}
+}
+
+func NewOAuthInvalidClientError() *OAuthError {
+	return &OAuthError{
+		Code:        constant.OAuthErrorInvalidClient,
+		Description: "client authentication failed",
+		StatusCode:  http.StatusUnauthorized,
+	}
+}
+
+func NewInsufficientScopeError() *apperror.AppError {
+	msg := constant.InsufficientScopeErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
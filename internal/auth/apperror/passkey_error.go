@@ -0,0 +1,42 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewPasskeyNotFoundError() *apperror.AppError {
+	msg := constant.PasskeyNotFoundErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.NotFoundErrorCode, msg)
+}
+
+func NewInvalidPasskeyIdError() *apperror.AppError {
+	msg := constant.InvalidPasskeyId
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewInvalidPasskeyError(err error) *apperror.AppError {
+	msg := constant.InvalidPasskeyErrorMessage
+
+	if err == nil {
+		err = errors.New(msg)
+	}
+
+	return apperror.NewAppError(err, apperror.UnauthorizedErrorCode, msg)
+}
+
+func NewInvalidPasskeyCeremonyError() *apperror.AppError {
+	msg := constant.InvalidPasskeyCeremonyErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
@@ -0,0 +1,49 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewInvalidCurrentPasswordError() *apperror.AppError {
+	msg := constant.InvalidCurrentPasswordErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewPasswordRequiredError() *apperror.AppError {
+	msg := constant.PasswordRequiredErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewSamePasswordError() *apperror.AppError {
+	msg := constant.SamePasswordErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewPasswordReusedError(historySize int) *apperror.AppError {
+	msg := fmt.Sprintf(constant.PasswordReusedErrorMessage, historySize)
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewBreachedPasswordError() *apperror.AppError {
+	msg := constant.BreachedPasswordErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
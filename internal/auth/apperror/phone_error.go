@@ -0,0 +1,40 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewPhoneNumberNotSetError() *apperror.AppError {
+	msg := constant.PhoneNumberNotSetErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewPhoneNumberVerifiedError() *apperror.AppError {
+	msg := constant.PhoneNumberVerifiedErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewInvalidPhoneOTPError() *apperror.AppError {
+	msg := constant.InvalidPhoneOTPErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewPhoneOTPLoginDisabledError() *apperror.AppError {
+	msg := constant.PhoneOTPLoginDisabledErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
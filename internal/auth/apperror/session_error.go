@@ -0,0 +1,48 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewSessionNotFoundError() *apperror.AppError {
+	msg := constant.SessionNotFoundErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.NotFoundErrorCode, msg)
+}
+
+func NewInvalidSessionIdError() *apperror.AppError {
+	msg := constant.InvalidSessionId
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewRefreshTokenReusedError() *apperror.AppError {
+	msg := constant.RefreshTokenReusedErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
+
+func NewRefreshTokenRotatedError() *apperror.AppError {
+	msg := constant.RefreshTokenRotatedErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewRecentLoginRequiredError() *apperror.AppError {
+	msg := constant.RecentLoginRequiredErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.ForbiddenAccessErrorCode, msg)
+}
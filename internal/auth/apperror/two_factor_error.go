@@ -0,0 +1,57 @@
+package apperror
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+)
+
+func NewTwoFactorAlreadyEnabledError() *apperror.AppError {
+	msg := constant.TwoFactorAlreadyEnabledErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewTwoFactorNotEnrolledError() *apperror.AppError {
+	msg := constant.TwoFactorNotEnrolledErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.NotFoundErrorCode, msg)
+}
+
+func NewTwoFactorNotEnabledError() *apperror.AppError {
+	msg := constant.TwoFactorNotEnabledErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.DefaultClientErrorCode, msg)
+}
+
+func NewInvalidTwoFactorCodeError() *apperror.AppError {
+	msg := constant.InvalidTwoFactorCodeErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.UnauthorizedErrorCode, msg)
+}
+
+func NewInvalidLoginChallengeError() *apperror.AppError {
+	msg := constant.InvalidLoginChallengeErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.UnauthorizedErrorCode, msg)
+}
+
+func NewTooManyTwoFactorAttemptsError(retryAfter time.Duration) *apperror.AppError {
+	msg := constant.TooManyTwoFactorAttemptsErrorMessage
+
+	err := errors.New(msg)
+
+	return apperror.NewAppError(err, apperror.TooManyRequestsErrorCode, msg).WithRetryAfter(retryAfter)
+}
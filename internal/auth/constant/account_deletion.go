@@ -0,0 +1,6 @@
+package constant
+
+const (
+	AccountDeletionRequestedReason = "deletion requested by the user"
+	AccountPurgedReason            = "personal data purged after the deletion grace period"
+)
@@ -0,0 +1,13 @@
+package constant
+
+import "time"
+
+const (
+	INACTIVE_USER = "inactive_user"
+	REACTIVATION  = "reactivation"
+)
+
+var (
+	ReactivationTokenExpireDuration   = 30 * time.Minute
+	ReactivationTokenCooldownDuration = 1 * time.Minute
+)
@@ -0,0 +1,13 @@
+package constant
+
+const (
+	AuditEventLogin               = "login"
+	AuditEventEmailChanged        = "email_changed"
+	AuditEventTwoFactorEnabled    = "two_factor_enabled"
+	AuditEventTwoFactorDisabled   = "two_factor_disabled"
+	AuditEventPasskeyRegistered   = "passkey_registered"
+	AuditEventPasskeyRemoved      = "passkey_removed"
+	AuditEventIdentityLinked      = "identity_linked"
+	AuditEventIdentityUnlinked    = "identity_unlinked"
+	AuditEventDataExportRequested = "data_export_requested"
+)
@@ -0,0 +1,17 @@
+package constant
+
+import "time"
+
+const (
+	DATA_EXPORT      = "data_export"
+	USER_DATA_EXPORT = "user_data_export"
+)
+
+const (
+	DataExportFormatJSON = "json"
+	DataExportFormatCSV  = "csv"
+)
+
+var (
+	DataExportCooldownDuration = 10 * time.Minute
+)
@@ -0,0 +1,13 @@
+package constant
+
+import "time"
+
+const (
+	EMAIL_CHANGE      = "email_change"
+	USER_EMAIL_CHANGE = "user_email_change"
+)
+
+var (
+	EmailChangeTokenExpireDuration   = 30 * time.Minute
+	EmailChangeTokenCooldownDuration = 1 * time.Minute
+)
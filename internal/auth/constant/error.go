@@ -20,4 +20,55 @@ const (
 	InvalidQueryisAssignAndRole          = "you cannot choose role 1 or 3 with is_assign 1 or 2"
 	UserDetailNotFoundErrorMessage       = "user detail not found"
 	AccountIsNotValidErrorMessage        = "account is not valid"
+	SessionNotFoundErrorMessage          = "session not found"
+	InvalidSessionId                     = "invalid session id"
+	RefreshTokenReusedErrorMessage       = "refresh token has already been used, please login again"
+	RefreshTokenRotatedErrorMessage      = "the session was just refreshed by another request, please use the latest tokens"
+	RecentLoginRequiredErrorMessage      = "please login again before continuing"
+	TwoFactorAlreadyEnabledErrorMessage  = "two-factor authentication is already enabled"
+	TwoFactorNotEnrolledErrorMessage     = "two-factor authentication enrollment not found"
+	TwoFactorNotEnabledErrorMessage      = "two-factor authentication is not enabled"
+	InvalidTwoFactorCodeErrorMessage     = "invalid two-factor authentication code"
+	InvalidLoginChallengeErrorMessage    = "login challenge is invalid or has expired, please login again"
+	TooManyTwoFactorAttemptsErrorMessage = "too many invalid two-factor authentication codes, please try again later"
+	PasskeyNotFoundErrorMessage          = "passkey not found"
+	InvalidPasskeyId                     = "invalid passkey id"
+	InvalidPasskeyErrorMessage           = "passkey verification failed"
+	InvalidPasskeyCeremonyErrorMessage   = "passkey ceremony is invalid or has expired, please try again"
+	AccountLockedErrorMessage            = "too many failed login attempts, your account is temporarily locked"
+	TooManyLoginAttemptsErrorMessage     = "too many failed login attempts, please try again later"
+	SameEmailErrorMessage                = "new email must be different from the current email"
+	OauthEmailChangeErrorMessage         = "the email of an oauth account is managed by its provider"
+	InvalidEmailChangeTokenErrorMessage  = "email change link is invalid or has expired, please request a new one"
+	InvalidCurrentPasswordErrorMessage   = "current password is incorrect"
+	SamePasswordErrorMessage             = "new password must be different from the current password"
+	PasswordReusedErrorMessage           = "new password must not match any of your last %d passwords"
+	BreachedPasswordErrorMessage         = "this password has appeared in a data breach, please choose a different one"
+	InvalidMagicLinkErrorMessage         = "sign-in link is invalid or has expired, please request a new one"
+	PhoneNumberNotSetErrorMessage        = "please add a phone number to your profile first"
+	PhoneNumberVerifiedErrorMessage      = "your phone number has been verified"
+	InvalidPhoneOTPErrorMessage          = "verification code is invalid or has expired, please request a new one"
+	PhoneOTPLoginDisabledErrorMessage    = "login with an sms code is not enabled"
+	AccountInactiveErrorMessage          = "your account is inactive"
+	InvalidReactivationTokenErrorMessage = "reactivation link is invalid or has expired, please request a new one"
+	ReactivationNotAllowedErrorMessage   = "your account was deactivated by an administrator, please contact support"
+	AccountSuspendedErrorMessage         = "your account is suspended"
+	AccountSuspendedUntilErrorMessage    = "your account is suspended until %s"
+	AccountPendingDeletionErrorMessage   = "your account is scheduled for deletion, use the link in our email to keep it"
+	InvalidStatusTransitionErrorMessage  = "account status cannot change from %s to %s"
+	SuspendedUntilPastErrorMessage       = "suspended until must be in the future"
+	PasswordRequiredErrorMessage         = "please set a password before deleting your account"
+	AccountDeletionEmailErrorMessage     = "your account is scheduled for deletion, but the email to keep it could not be sent, request a new reactivation link to keep it"
+	DataExportAlreadyRequestedMessage    = "a data export was requested recently, please wait before requesting another"
+	InvalidDataExportLinkErrorMessage    = "download link is invalid or has expired, please request a new export"
+	IdentityNotFoundErrorMessage         = "linked identity not found"
+	InvalidIdentityId                    = "invalid identity id"
+	UnsupportedOauthProviderErrorMessage = "oauth provider %s is not supported"
+	IdentityAlreadyLinkedErrorMessage    = "this %s account is already linked to another user"
+	OauthAccountNotLinkedErrorMessage    = "an account with this email already exists, sign in and link %s from your account settings"
+	InvalidOauthLinkErrorMessage         = "account link is invalid or has expired, please try again"
+	LastLoginMethodErrorMessage          = "you cannot remove your only way to sign in"
+	OauthEmailMissingErrorMessage        = "%s did not share an email address, please allow access to it and try again"
+	OauthEmailNotVerifiedErrorMessage    = "%s has not verified your email address, please verify it with %s and try again"
+	InsufficientScopeErrorMessage        = "the access token was not granted the scope this endpoint requires"
 )
@@ -0,0 +1,11 @@
+package constant
+
+import "time"
+
+const (
+	OAUTH_LINK = "oauth_link"
+)
+
+var (
+	OauthLinkExpireDuration = 10 * time.Minute
+)
@@ -0,0 +1,13 @@
+package constant
+
+const (
+	LOGIN_FAILURES   = "login_failures"
+	LOGIN_DELAY      = "login_delay"
+	LOGIN_LOCK       = "login_lock"
+	LOGIN_FAILED_IPS = "login_failed_ips"
+)
+
+const (
+	LoginAttemptScopeEmail = "email"
+	LoginAttemptScopeIP    = "ip"
+)
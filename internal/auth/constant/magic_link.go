@@ -0,0 +1,13 @@
+package constant
+
+import "time"
+
+const (
+	MAGIC_LINK      = "magic_link"
+	MAGIC_LINK_USER = "magic_link_user"
+)
+
+var (
+	MagicLinkTokenExpireDuration   = 15 * time.Minute
+	MagicLinkTokenCooldownDuration = 1 * time.Minute
+)
@@ -0,0 +1,34 @@
+package constant
+
+import "time"
+
+const (
+	OIDC_CODE = "oidc_code"
+)
+
+const (
+	OIDC_SCOPE_OPENID  = "openid"
+	OIDC_SCOPE_PROFILE = "profile"
+	OIDC_SCOPE_EMAIL   = "email"
+	OIDC_SCOPE_PHONE   = "phone"
+)
+
+const (
+	OIDC_RESPONSE_TYPE_CODE         = "code"
+	OIDC_GRANT_TYPE_AUTHORIZATION   = "authorization_code"
+	OIDC_GRANT_TYPE_REFRESH_TOKEN   = "refresh_token"
+	OIDC_CODE_CHALLENGE_METHOD_S256 = "S256"
+)
+
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+)
+
+var (
+	OIDCCodeExpireDuration = 1 * time.Minute
+)
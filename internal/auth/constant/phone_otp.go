@@ -0,0 +1,23 @@
+package constant
+
+import "time"
+
+const (
+	PHONE_OTP          = "phone_otp"
+	PHONE_OTP_ATTEMPTS = "phone_otp_attempts"
+)
+
+const (
+	PhoneOTPPurposeVerify = "verify"
+	PhoneOTPPurposeLogin  = "login"
+)
+
+const (
+	PhoneOTPLength      = 6
+	PhoneOTPMaxAttempts = 5
+)
+
+var (
+	PhoneOTPExpireDuration   = 5 * time.Minute
+	PhoneOTPCooldownDuration = 1 * time.Minute
+)
@@ -1,7 +1,44 @@
 package constant
 
+import "time"
+
 const (
 	SESSION_ID    = "session_id"
 	SESSION_NAME  = "auth-session"
 	REFRESH_TOKEN = "refresh_token"
+	USER_SESSIONS = "user_sessions"
+)
+
+const (
+	LoginMethodPassword    = "password"
+	LoginMethodPasskey     = "passkey"
+	LoginMethodMagicLink   = "magic_link"
+	LoginMethodSMSOTP      = "sms_otp"
+	LoginMethodOIDC        = "oidc"
+	LoginMethodOauthPrefix = "oauth:"
+	LoginMethodTOTPSuffix  = "+totp"
+)
+
+const (
+	AUTH_MODE_HEADER  = "X-Auth-Mode"
+	AUTH_MODE_TOKEN   = "token"
+	BEARER_TOKEN_TYPE = "Bearer"
+)
+
+// SessionRefreshAllAttempts is how often a session that keeps being
+// refreshed concurrently is read again when its role changes.
+const SessionRefreshAllAttempts = 3
+
+var (
+	SessionExpireDuration = 24 * time.Hour
+	// SessionTouchInterval throttles how often activity pushes the idle
+	// timeout of a session forward.
+	SessionTouchInterval = time.Minute
+	// RefreshTokenRotationGrace is how long the refresh token that was just
+	// rotated out is answered with a conflict instead of being treated as
+	// reuse, so two tabs refreshing at once do not end the session.
+	RefreshTokenRotationGrace = 30 * time.Second
+	// RecentLoginDuration is how old a login may be to act in place of the
+	// current password, e.g. when an oauth-only account sets its first one.
+	RecentLoginDuration = 5 * time.Minute
 )
@@ -0,0 +1,21 @@
+package constant
+
+import "time"
+
+const (
+	LOGIN_CHALLENGE          = "login_challenge"
+	LOGIN_CHALLENGE_ATTEMPTS = "login_challenge_attempts"
+	TWO_FACTOR_ATTEMPTS      = "two_factor_attempts"
+)
+
+const (
+	LoginChallengeMaxAttempts = 5
+	TwoFactorMaxAttempts      = 5
+)
+
+var (
+	LoginChallengeExpireDuration = 5 * time.Minute
+	// TwoFactorAttemptsWindow is how long the codes entered to confirm or
+	// disable two-factor authentication are counted against a user.
+	TwoFactorAttemptsWindow = 15 * time.Minute
+)
@@ -0,0 +1,12 @@
+package constant
+
+import "time"
+
+const (
+	WEBAUTHN_REGISTRATION = "webauthn_registration"
+	WEBAUTHN_LOGIN        = "webauthn_login"
+)
+
+var (
+	WebAuthnCeremonyExpireDuration = 5 * time.Minute
+)
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/faisalyudiansah/auth-service-template/configs/logstash"
 	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
@@ -14,6 +15,7 @@ import (
 	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
 	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+	sessioncookieutils "github.com/faisalyudiansah/auth-service-template/pkg/utils/sessionCookieUtils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -41,15 +43,119 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	res, session, err := c.authUsecase.Login(ctx, req)
+	req.ClientIP = ctx.ClientIP()
+	res, session, challenge, err := c.authUsecase.Login(ctx, req)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	utils.SetSessionCookie(ctx, session.SessionID)
+	respondLoginOrChallenge(ctx, res, session, challenge)
+}
+
+func (c *AuthController) RequestMagicLink(ctx *gin.Context) {
+	req := new(dto_request.RequestMagicLink)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.authUsecase.RequestMagicLink(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	ginutils.ResponseOK(ctx, converterAuth.UserEntityToDTOLogin(res))
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+func (c *AuthController) LoginMagicLink(ctx *gin.Context) {
+	req := new(dto_request.MagicLinkLogin)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.ClientIP = ctx.ClientIP()
+	res, session, challenge, err := c.authUsecase.LoginMagicLink(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondLoginOrChallenge(ctx, res, session, challenge)
+}
+
+func (c *AuthController) LoginTwoFactor(ctx *gin.Context) {
+	req := new(dto_request.LoginTwoFactor)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, session, err := c.authUsecase.LoginTwoFactor(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondLogin(ctx, res, session)
+}
+
+func respondLoginOrChallenge(ctx *gin.Context, user *entity.User, session *entity.Session, challenge *entity.LoginChallenge) {
+	if challenge != nil {
+		resLogin := converterAuth.UserEntityToDTOLogin(user)
+		resLogin.TwoFactorRequired = true
+		resLogin.Challenge = converterAuth.LoginChallengeEntityToDTOResponse(challenge)
+		ginutils.ResponseOK(ctx, resLogin)
+		return
+	}
+
+	respondLogin(ctx, user, session)
+}
+
+func (c *AuthController) RequestPhoneOTP(ctx *gin.Context) {
+	req := new(dto_request.RequestPhoneOTP)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.authUsecase.RequestPhoneOTP(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+func (c *AuthController) LoginPhoneOTP(ctx *gin.Context) {
+	req := new(dto_request.PhoneOTPLogin)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, session, challenge, err := c.authUsecase.LoginPhoneOTP(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondLoginOrChallenge(ctx, res, session, challenge)
+}
+
+// respondLogin hands a freshly created session to the client, either as a
+// token pair or as the session cookie, depending on the requested auth mode.
+func respondLogin(ctx *gin.Context, user *entity.User, session *entity.Session) {
+	resLogin := converterAuth.UserEntityToDTOLogin(user)
+
+	if utils.IsTokenMode(ctx) {
+		resLogin.Token = converterAuth.SessionEntityToDTOToken(session)
+	} else {
+		utils.SetSessionCookie(ctx, session.SessionID, time.Until(session.ExpiresAt))
+	}
+
+	ginutils.ResponseOK(ctx, resLogin)
 }
 
 func (c *AuthController) Logout(ctx *gin.Context) {
@@ -63,8 +169,30 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 	ginutils.ResponseOKPlain(ctx)
 }
 
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	if err := c.authUsecase.LogoutAll(ctx, utils.GetValueUserIDFromContext(ctx)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.ClearSessionCookie(ctx)
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
-	res, err := c.authUsecase.RefreshToken(ctx, utils.GetValueSessionIDFromContext(ctx))
+	if utils.IsTokenMode(ctx) {
+		c.refreshTokenByToken(ctx)
+		return
+	}
+
+	sessionID, err := sessioncookieutils.GetSessionIDFromCookie(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := c.authUsecase.RefreshToken(ctx, sessionID)
 	if err != nil {
 		if appErr, ok := err.(*apperrorPkg.AppError); ok && appErr.Error() == apperrorPkg.NewSessionExpiredError().Error() {
 			utils.ClearSessionCookie(ctx)
@@ -73,11 +201,27 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	utils.SetSessionCookie(ctx, res.SessionID)
+	utils.SetSessionCookie(ctx, res.SessionID, time.Until(res.ExpiresAt))
 
 	ginutils.ResponseOKPlain(ctx)
 }
 
+func (c *AuthController) refreshTokenByToken(ctx *gin.Context) {
+	req := new(dto_request.RefreshToken)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, err := c.authUsecase.RefreshTokenByToken(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.SessionEntityToDTOToken(res))
+}
+
 func (c *AuthController) Register(ctx *gin.Context) {
 	req := new(dto_request.Register)
 
@@ -94,7 +238,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 			return err
 		}
 
-		if !res.IsVerified {
+		if !res.IsVerified() {
 			if err := c.authUsecase.SendVerification(txCtx, &dto_request.SendVerification{Email: res.Email}); err != nil {
 				return err
 			}
@@ -189,12 +333,95 @@ func (c *AuthController) ResetPassword(ctx *gin.Context) {
 	ginutils.ResponseOKPlain(ctx)
 }
 
+func (c *AuthController) RequestEmailChange(ctx *gin.Context) {
+	req := new(dto_request.ChangeEmail)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	req.SessionID = utils.GetValueSessionIDFromContext(ctx)
+	if err := c.authUsecase.RequestEmailChange(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+func (c *AuthController) ConfirmEmailChange(ctx *gin.Context) {
+	req := new(dto_request.ConfirmEmailChange)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.authUsecase.ConfirmEmailChange(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
 func (c *AuthController) InactiveAccount(ctx *gin.Context) {
 	modulName := "AuthController.InactiveAccount"
 
 	req := new(dto_request.InactiveAccount)
+	req.UpdatedBy = utils.GetValueUserIDFromContext(ctx)
+	req.UserID = req.UpdatedBy
+
+	// without a user id in the path the caller deactivates their own account
+	if userIDstr := ctx.Param("user_id"); userIDstr != "" {
+		userID, err := uuid.Parse(userIDstr)
+		if err != nil {
+			logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+			ctx.Error(apperrorAuth.NewInvalidUserIdError())
+			return
+		}
+		req.UserID = userID
+	}
+
+	logstash.LogstashRequestInfo(ctx, req, fmt.Sprintf("%v - REQUEST : %s", modulName, req.UserID))
+	if err := c.authUsecase.InactiveAccount(ctx, req); err != nil {
+		logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - USECASE : %s", modulName+".InactiveAccount", req.UserID))
+		ctx.Error(err)
+		return
+	}
+
+	if req.UserID == req.UpdatedBy {
+		utils.ClearSessionCookie(ctx)
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *AuthController) UnlockAccount(ctx *gin.Context) {
+	modulName := "AuthController.UnlockAccount"
+
 	userIDstr := ctx.Param("user_id")
+	userID, err := uuid.Parse(userIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidUserIdError())
+		return
+	}
+
+	logstash.LogstashRequestInfo(ctx, userIDstr, fmt.Sprintf("%v - REQUEST : %v", modulName, utils.GetValueUserIDFromContext(ctx)))
+	if err := c.authUsecase.UnlockAccount(ctx, userID); err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - USECASE : %s", modulName, userID))
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *AuthController) SuspendAccount(ctx *gin.Context) {
+	modulName := "AuthController.SuspendAccount"
 
+	userIDstr := ctx.Param("user_id")
 	userID, err := uuid.Parse(userIDstr)
 	if err != nil {
 		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
@@ -202,25 +429,78 @@ func (c *AuthController) InactiveAccount(ctx *gin.Context) {
 		return
 	}
 
+	req := new(dto_request.SuspendAccount)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - BIND JSON", modulName))
+		ctx.Error(err)
+		return
+	}
+
 	req.UserID = userID
 	req.UpdatedBy = utils.GetValueUserIDFromContext(ctx)
 
 	logstash.LogstashRequestInfo(ctx, req, fmt.Sprintf("%v - REQUEST : %s", modulName, req.UserID))
-	if err := c.authUsecase.InactiveAccount(ctx, req); err != nil {
-		logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - USECASE : %s", modulName+".InactiveAccount", req.UserID))
+	if err := c.authUsecase.SuspendAccount(ctx, req); err != nil {
+		logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - USECASE : %s", modulName, req.UserID))
 		ctx.Error(err)
 		return
 	}
 
-	if req.UserID == req.UpdatedBy {
-		if err := c.authUsecase.Logout(ctx, req.UserID); err != nil {
-			logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - USECASE : %s", modulName+".Logout", req.UserID))
-			ctx.Error(err)
-			return
-		}
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *AuthController) ActivateAccount(ctx *gin.Context) {
+	modulName := "AuthController.ActivateAccount"
+
+	userIDstr := ctx.Param("user_id")
+	userID, err := uuid.Parse(userIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidUserIdError())
+		return
 	}
 
-	utils.ClearSessionCookie(ctx)
+	req := &dto_request.ActivateAccount{
+		UserID:    userID,
+		UpdatedBy: utils.GetValueUserIDFromContext(ctx),
+	}
+
+	logstash.LogstashRequestInfo(ctx, req, fmt.Sprintf("%v - REQUEST : %s", modulName, req.UserID))
+	if err := c.authUsecase.ActivateAccount(ctx, req); err != nil {
+		logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - USECASE : %s", modulName, req.UserID))
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *AuthController) RequestReactivation(ctx *gin.Context) {
+	req := new(dto_request.RequestReactivation)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.authUsecase.RequestReactivation(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+func (c *AuthController) ConfirmReactivation(ctx *gin.Context) {
+	req := new(dto_request.ConfirmReactivation)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := c.authUsecase.ConfirmReactivation(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
 
 	ginutils.ResponseOKPlain(ctx)
 }
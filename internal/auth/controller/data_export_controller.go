@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/faisalyudiansah/auth-service-template/configs/logstash"
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DataExportController struct {
+	dataExportUsecase usecase.DataExportUsecase
+}
+
+func NewDataExportController(
+	dataExportUsecase usecase.DataExportUsecase,
+) *DataExportController {
+	return &DataExportController{
+		dataExportUsecase: dataExportUsecase,
+	}
+}
+
+func (c *DataExportController) RequestMyExport(ctx *gin.Context) {
+	req := new(dto_request.RequestDataExport)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	req.RequestedBy = req.UserID
+	if err := c.dataExportUsecase.Request(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+func (c *DataExportController) RequestUserExport(ctx *gin.Context) {
+	modulName := "DataExportController.RequestUserExport"
+
+	req := new(dto_request.RequestDataExport)
+	userIDstr := ctx.Param("user_id")
+
+	userID, err := uuid.Parse(userIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidUserIdError())
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		logstash.LogstashError(ctx, err, nil, fmt.Sprintf("%v - BIND", modulName))
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = userID
+	req.RequestedBy = utils.GetValueUserIDFromContext(ctx)
+
+	logstash.LogstashRequestInfo(ctx, req, fmt.Sprintf("%v - REQUEST : %v", modulName, req.UserID))
+	if err := c.dataExportUsecase.Request(ctx, req); err != nil {
+		logstash.LogstashError(ctx, err, req, fmt.Sprintf("%v - USECASE : %s", modulName, req.UserID))
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+// Download takes the token of the emailed link in the body, the link carries
+// it in its fragment so it never reaches a server log.
+func (c *DataExportController) Download(ctx *gin.Context) {
+	req := new(dto_request.DownloadDataExport)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+
+	export, data, err := c.dataExportUsecase.Download(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, export.ContentType, data)
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/configs/logstash"
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	converterAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/converter"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type IdentityController struct {
+	identityUsecase usecase.IdentityUsecase
+}
+
+func NewIdentityController(
+	identityUsecase usecase.IdentityUsecase,
+) *IdentityController {
+	return &IdentityController{
+		identityUsecase: identityUsecase,
+	}
+}
+
+func (c *IdentityController) GetMyIdentities(ctx *gin.Context) {
+	res, err := c.identityUsecase.GetListByUserID(ctx, utils.GetValueUserIDFromContext(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.ListUserIdentityEntityToDTOResponse(res))
+}
+
+func (c *IdentityController) LinkMyIdentity(ctx *gin.Context) {
+	req := new(dto_request.LinkIdentity)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	res, err := c.identityUsecase.BeginLink(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.OauthLinkEntityToDTOResponse(res))
+}
+
+func (c *IdentityController) UnlinkMyIdentity(ctx *gin.Context) {
+	modulName := "IdentityController.UnlinkMyIdentity"
+
+	identityIDstr := ctx.Param("identity_id")
+	identityID, err := uuid.Parse(identityIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, identityIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidIdentityIdError())
+		return
+	}
+
+	if err := c.identityUsecase.Unlink(ctx, utils.GetValueUserIDFromContext(ctx), identityID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
@@ -1,12 +1,18 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/config"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/oauthutils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/markbates/goth/gothic"
@@ -27,6 +33,35 @@ func NewOauthController(
 	}
 }
 
+// RequireProvider stops requests for a provider that is not enabled before
+// gothic gets to them.
+func (c *OauthController) RequireProvider(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+	if !oauthutils.IsEnabled(provider) {
+		ctx.Error(apperrorAuth.NewUnsupportedOauthProviderError(provider))
+		ctx.Abort()
+		return
+	}
+
+	ctx.Next()
+}
+
+func (c *OauthController) GetProviders(ctx *gin.Context) {
+	res := make([]dto_response.OauthProvider, 0, len(c.cfgConfig.Oauth.ProviderNames))
+
+	for _, name := range c.cfgConfig.Oauth.ProviderNames {
+		if !oauthutils.IsEnabled(name) {
+			continue
+		}
+		res = append(res, dto_response.OauthProvider{
+			Name:     name,
+			LoginURL: fmt.Sprintf("%s/oauth/%s/login", c.cfgConfig.OIDC.Issuer, name),
+		})
+	}
+
+	ginutils.ResponseOK(ctx, res)
+}
+
 func (c *OauthController) Login(ctx *gin.Context) {
 	provider := ctx.Param("provider")
 
@@ -44,13 +79,49 @@ func (c *OauthController) Callback(ctx *gin.Context) {
 		return
 	}
 
-	_, session, err := c.oauthUsecase.Login(ctx, &user)
+	linked, err := c.oauthUsecase.Link(ctx, ctx.Query("state"), utils.GetValueUserIDFromContext(ctx), &user)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	utils.SetSessionCookie(ctx, session.SessionID)
+	if linked {
+		redirectURL, err := url.Parse(c.cfgConfig.URLClientConfig.URLClientOauthCallback)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		query := redirectURL.Query()
+		query.Set("linked", user.Provider)
+		redirectURL.RawQuery = query.Encode()
+
+		ctx.Redirect(http.StatusFound, redirectURL.String())
+		return
+	}
+
+	_, session, challenge, err := c.oauthUsecase.Login(ctx, &user)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if challenge != nil {
+		redirectURL, err := url.Parse(c.cfgConfig.URLClientConfig.URLClientOauthCallback)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		query := redirectURL.Query()
+		query.Set("challenge_id", challenge.ID.String())
+		redirectURL.RawQuery = query.Encode()
+
+		ctx.Redirect(http.StatusFound, redirectURL.String())
+		return
+	}
+
+	utils.SetSessionCookie(ctx, session.SessionID, time.Until(session.ExpiresAt))
 
 	ctx.Redirect(http.StatusFound, c.cfgConfig.URLClientConfig.URLClientOauthCallback)
 }
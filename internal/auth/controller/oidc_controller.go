@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	converterAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/converter"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OidcController struct {
+	oidcUsecase usecase.OidcUsecase
+	cfgConfig   *config.Config
+}
+
+func NewOidcController(
+	oidcUsecase usecase.OidcUsecase,
+	cfgConfig *config.Config,
+) *OidcController {
+	return &OidcController{
+		oidcUsecase: oidcUsecase,
+		cfgConfig:   cfgConfig,
+	}
+}
+
+func (c *OidcController) Authorize(ctx *gin.Context) {
+	req := new(dto_request.OIDCAuthorize)
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		c.oauthError(ctx, apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidRequest, "client_id and redirect_uri are required"))
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	req.SessionID = utils.GetValueSessionIDFromContext(ctx)
+	if req.UserID == uuid.Nil {
+		loginURL, err := url.Parse(c.cfgConfig.OIDC.LoginURL)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		query := loginURL.Query()
+		query.Set("return_to", c.cfgConfig.OIDC.Issuer+ctx.Request.URL.RequestURI())
+		loginURL.RawQuery = query.Encode()
+
+		ctx.Redirect(http.StatusFound, loginURL.String())
+		return
+	}
+
+	redirectURL, err := c.oidcUsecase.Authorize(ctx, req)
+	if err != nil {
+		c.oauthError(ctx, err)
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
+
+func (c *OidcController) Token(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	req := new(dto_request.OIDCToken)
+	if err := ctx.ShouldBind(req); err != nil {
+		c.oauthError(ctx, apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidRequest, "grant_type is required"))
+		return
+	}
+
+	if clientID, clientSecret, ok := ctx.Request.BasicAuth(); ok {
+		req.ClientID, _ = url.QueryUnescape(clientID)
+		req.ClientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+
+	res, err := c.oidcUsecase.Token(ctx, req)
+	if err != nil {
+		c.oauthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, converterAuth.OIDCTokenEntityToDTOResponse(res, c.cfgConfig.Jwt.TokenDuration*60))
+}
+
+func (c *OidcController) UserInfo(ctx *gin.Context) {
+	user, scope, err := c.oidcUsecase.UserInfo(ctx, utils.GetValueUserIDFromContext(ctx), utils.GetValueSessionIDFromContext(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, converterAuth.UserEntityToDTOOIDCUserInfo(user, scope))
+}
+
+func (c *OidcController) oauthError(ctx *gin.Context, err error) {
+	var oauthErr *apperrorAuth.OAuthError
+	if errors.As(err, &oauthErr) {
+		if oauthErr.StatusCode == http.StatusUnauthorized {
+			ctx.Header("WWW-Authenticate", `Basic realm="token"`)
+		}
+		ctx.AbortWithStatusJSON(oauthErr.StatusCode, oauthErr)
+		return
+	}
+
+	ctx.Error(err)
+}
@@ -128,6 +128,67 @@ func (c *ProfileController) UpdateUser(ctx *gin.Context) {
 	ginutils.ResponseOK(ctx, converterAuth.UserEntityToDTOResponse(res))
 }
 
+func (c *ProfileController) ChangePassword(ctx *gin.Context) {
+	req := new(dto_request.ChangePassword)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	req.SessionID = utils.GetValueSessionIDFromContext(ctx)
+
+	if err := c.profileUsecase.ChangePassword(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *ProfileController) RequestAccountDeletion(ctx *gin.Context) {
+	req := new(dto_request.RequestAccountDeletion)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	if err := c.profileUsecase.RequestAccountDeletion(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.ClearSessionCookie(ctx)
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *ProfileController) SendPhoneVerification(ctx *gin.Context) {
+	if err := c.profileUsecase.SendPhoneVerification(ctx, utils.GetValueUserIDFromContext(ctx)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreatedPlain(ctx)
+}
+
+func (c *ProfileController) VerifyPhoneNumber(ctx *gin.Context) {
+	req := new(dto_request.VerifyPhoneNumber)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	if err := c.profileUsecase.VerifyPhoneNumber(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
 func (c *ProfileController) DeleteUser(ctx *gin.Context) {
 	modulName := "ProfileController.DeleteUser"
 
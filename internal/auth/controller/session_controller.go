@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/configs/logstash"
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	converterAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/converter"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionController struct {
+	sessionUsecase usecase.SessionUsecase
+}
+
+func NewSessionController(
+	sessionUsecase usecase.SessionUsecase,
+) *SessionController {
+	return &SessionController{
+		sessionUsecase: sessionUsecase,
+	}
+}
+
+func (c *SessionController) GetMySessions(ctx *gin.Context) {
+	res, err := c.sessionUsecase.GetListByUserID(ctx, utils.GetValueUserIDFromContext(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.ListSessionEntityToDTOResponse(res, utils.GetValueSessionIDFromContext(ctx)))
+}
+
+func (c *SessionController) RevokeMySession(ctx *gin.Context) {
+	sessionID, err := uuid.Parse(ctx.Param("session_id"))
+	if err != nil {
+		ctx.Error(apperrorAuth.NewInvalidSessionIdError())
+		return
+	}
+
+	if err := c.sessionUsecase.Revoke(ctx, utils.GetValueUserIDFromContext(ctx), sessionID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if sessionID == utils.GetValueSessionIDFromContext(ctx) {
+		utils.ClearSessionCookie(ctx)
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *SessionController) GetUserSessions(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		ctx.Error(apperrorAuth.NewInvalidUserIdError())
+		return
+	}
+
+	res, err := c.sessionUsecase.GetListByUserID(ctx, userID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.ListSessionEntityToDTOResponse(res, utils.GetValueSessionIDFromContext(ctx)))
+}
+
+func (c *SessionController) RevokeUserSession(ctx *gin.Context) {
+	modulName := "SessionController.RevokeUserSession"
+
+	userIDstr := ctx.Param("user_id")
+	userID, err := uuid.Parse(userIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidUserIdError())
+		return
+	}
+
+	sessionIDstr := ctx.Param("session_id")
+	sessionID, err := uuid.Parse(sessionIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, sessionIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidSessionIdError())
+		return
+	}
+
+	logstash.LogstashRequestInfo(ctx, sessionIDstr, fmt.Sprintf("%v - REQUEST : %v", modulName, userID))
+	if err := c.sessionUsecase.Revoke(ctx, userID, sessionID); err != nil {
+		logstash.LogstashError(ctx, err, sessionIDstr, fmt.Sprintf("%v - USECASE : %s", modulName, userID))
+		ctx.Error(err)
+		return
+	}
+
+	if sessionID == utils.GetValueSessionIDFromContext(ctx) {
+		utils.ClearSessionCookie(ctx)
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/configs/logstash"
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	converterAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/converter"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TwoFactorController struct {
+	twoFactorUsecase usecase.TwoFactorUsecase
+}
+
+func NewTwoFactorController(
+	twoFactorUsecase usecase.TwoFactorUsecase,
+) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorUsecase: twoFactorUsecase,
+	}
+}
+
+func (c *TwoFactorController) Enroll(ctx *gin.Context) {
+	res, err := c.twoFactorUsecase.Enroll(ctx, utils.GetValueUserIDFromContext(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.TOTPEnrollmentEntityToDTOResponse(res))
+}
+
+func (c *TwoFactorController) Confirm(ctx *gin.Context) {
+	req := new(dto_request.ConfirmTwoFactor)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	if err := c.twoFactorUsecase.Confirm(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *TwoFactorController) Disable(ctx *gin.Context) {
+	req := new(dto_request.DisableTwoFactor)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	if err := c.twoFactorUsecase.Disable(ctx, req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *TwoFactorController) Reset(ctx *gin.Context) {
+	modulName := "TwoFactorController.Reset"
+
+	userIDstr := ctx.Param("user_id")
+	userID, err := uuid.Parse(userIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidUserIdError())
+		return
+	}
+
+	logstash.LogstashRequestInfo(ctx, userIDstr, fmt.Sprintf("%v - REQUEST : %v", modulName, utils.GetValueUserIDFromContext(ctx)))
+	if err := c.twoFactorUsecase.Reset(ctx, userID); err != nil {
+		logstash.LogstashError(ctx, err, userIDstr, fmt.Sprintf("%v - USECASE : %s", modulName, userID))
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
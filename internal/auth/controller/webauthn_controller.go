@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/configs/logstash"
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	converterAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/converter"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ginutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebAuthnController struct {
+	webAuthnUsecase usecase.WebAuthnUsecase
+}
+
+func NewWebAuthnController(
+	webAuthnUsecase usecase.WebAuthnUsecase,
+) *WebAuthnController {
+	return &WebAuthnController{
+		webAuthnUsecase: webAuthnUsecase,
+	}
+}
+
+func (c *WebAuthnController) BeginRegistration(ctx *gin.Context) {
+	res, err := c.webAuthnUsecase.BeginRegistration(ctx, utils.GetValueUserIDFromContext(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.WebAuthnCeremonyEntityToDTOResponse(res))
+}
+
+func (c *WebAuthnController) FinishRegistration(ctx *gin.Context) {
+	req := new(dto_request.FinishPasskeyRegistration)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	res, err := c.webAuthnUsecase.FinishRegistration(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseCreated(ctx, converterAuth.WebAuthnCredentialEntityToDTOResponse(res))
+}
+
+func (c *WebAuthnController) GetMyPasskeys(ctx *gin.Context) {
+	res, err := c.webAuthnUsecase.GetListByUserID(ctx, utils.GetValueUserIDFromContext(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.ListWebAuthnCredentialEntityToDTOResponse(res))
+}
+
+func (c *WebAuthnController) RenameMyPasskey(ctx *gin.Context) {
+	modulName := "WebAuthnController.RenameMyPasskey"
+
+	passkeyIDstr := ctx.Param("passkey_id")
+	passkeyID, err := uuid.Parse(passkeyIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, passkeyIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidPasskeyIdError())
+		return
+	}
+
+	req := new(dto_request.RenamePasskey)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	req.ID = passkeyID
+	req.UserID = utils.GetValueUserIDFromContext(ctx)
+	res, err := c.webAuthnUsecase.Rename(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.WebAuthnCredentialEntityToDTOResponse(res))
+}
+
+func (c *WebAuthnController) DeleteMyPasskey(ctx *gin.Context) {
+	modulName := "WebAuthnController.DeleteMyPasskey"
+
+	passkeyIDstr := ctx.Param("passkey_id")
+	passkeyID, err := uuid.Parse(passkeyIDstr)
+	if err != nil {
+		logstash.LogstashError(ctx, err, passkeyIDstr, fmt.Sprintf("%v - PARSE UUID", modulName))
+		ctx.Error(apperrorAuth.NewInvalidPasskeyIdError())
+		return
+	}
+
+	if err := c.webAuthnUsecase.Delete(ctx, utils.GetValueUserIDFromContext(ctx), passkeyID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOKPlain(ctx)
+}
+
+func (c *WebAuthnController) BeginLogin(ctx *gin.Context) {
+	res, err := c.webAuthnUsecase.BeginLogin(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ginutils.ResponseOK(ctx, converterAuth.WebAuthnCeremonyEntityToDTOResponse(res))
+}
+
+func (c *WebAuthnController) FinishLogin(ctx *gin.Context) {
+	req := new(dto_request.FinishPasskeyLogin)
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	res, session, err := c.webAuthnUsecase.FinishLogin(ctx, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondLogin(ctx, res, session)
+}
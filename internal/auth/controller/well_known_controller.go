@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownController struct {
+	jwtUtil   jwtutils.JwtUtilInterface
+	cfgConfig *config.Config
+}
+
+func NewWellKnownController(
+	jwtUtil jwtutils.JwtUtilInterface,
+	cfgConfig *config.Config,
+) *WellKnownController {
+	return &WellKnownController{
+		jwtUtil:   jwtUtil,
+		cfgConfig: cfgConfig,
+	}
+}
+
+func (c *WellKnownController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.jwtUtil.JWKS())
+}
+
+func (c *WellKnownController) OpenIDConfiguration(ctx *gin.Context) {
+	issuer := c.cfgConfig.OIDC.Issuer
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, dto_response.OIDCDiscovery{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth2/authorize",
+		TokenEndpoint:                     issuer + "/oauth2/token",
+		UserInfoEndpoint:                  issuer + "/userinfo",
+		JwksURI:                           issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{constantAuth.OIDC_RESPONSE_TYPE_CODE},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{c.jwtUtil.SigningAlg()},
+		ScopesSupported:                   []string{constantAuth.OIDC_SCOPE_OPENID, constantAuth.OIDC_SCOPE_PROFILE, constantAuth.OIDC_SCOPE_EMAIL, constantAuth.OIDC_SCOPE_PHONE},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		GrantTypesSupported:               []string{constantAuth.OIDC_GRANT_TYPE_AUTHORIZATION, constantAuth.OIDC_GRANT_TYPE_REFRESH_TOKEN},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "sid", "email", "email_verified", "name", "picture", "gender", "birthdate", "phone_number", "updated_at"},
+		CodeChallengeMethodsSupported:     []string{constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+	})
+}
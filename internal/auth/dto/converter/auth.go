@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"time"
+
 	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
 )
@@ -10,13 +12,17 @@ func UserEntityToDTOLogin(user *entity.User) *dto_response.Login {
 		return nil
 	}
 
+	status := user.EffectiveStatus(time.Now())
+
 	return &dto_response.Login{
-		Email:      user.Email,
-		IsVerified: user.IsVerified,
-		IsOauth:    user.IsOauth,
-		IsActive:   user.IsActive,
-		Role:       user.Role,
-		RoleLabel:  user.Role.String(),
+		Email:       user.Email,
+		IsVerified:  user.IsVerified(),
+		IsOauth:     user.IsOauth,
+		IsActive:    status.IsUserStatusActive(),
+		Status:      status,
+		StatusLabel: status.String(),
+		Role:        user.Role,
+		RoleLabel:   user.Role.String(),
 	}
 }
 
@@ -25,18 +31,43 @@ func UserEntityToDTORegister(user *entity.User) *dto_response.Register {
 		return nil
 	}
 
+	status := user.EffectiveStatus(time.Now())
+
 	return &dto_response.Register{
-		ID:         user.ID,
-		Email:      user.Email,
-		FullName:   user.UserDetail.FullName,
-		IsVerified: user.IsVerified,
-		IsOauth:    user.IsOauth,
-		IsActive:   user.IsActive,
-		Role:       user.Role,
-		RoleLabel:  user.Role.String(),
-		Sex:        user.UserDetail.Sex,
-		SexLabel:   user.UserDetail.Sex.String(),
-		BirthDate:  user.UserDetail.BirthDate,
-		CreatedAt:  user.CreatedAt,
+		ID:          user.ID,
+		Email:       user.Email,
+		FullName:    user.UserDetail.FullName,
+		IsVerified:  user.IsVerified(),
+		IsOauth:     user.IsOauth,
+		IsActive:    status.IsUserStatusActive(),
+		Status:      status,
+		StatusLabel: status.String(),
+		Role:        user.Role,
+		RoleLabel:   user.Role.String(),
+		Sex:         user.UserDetail.Sex,
+		SexLabel:    user.UserDetail.Sex.String(),
+		BirthDate:   user.UserDetail.BirthDate,
+		CreatedAt:   user.CreatedAt,
+	}
+}
+
+func LoginChallengeEntityToDTOResponse(e *entity.LoginChallenge) *dto_response.LoginChallenge {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.LoginChallenge{
+		ChallengeID: e.ID,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+func TOTPEnrollmentEntityToDTOResponse(e *entity.TOTPEnrollment) *dto_response.TwoFactorEnrollment {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.TwoFactorEnrollment{
+		Secret:     e.Secret,
+		OtpauthURI: e.OtpauthURI,
+		QRCode:     e.QRCode,
 	}
 }
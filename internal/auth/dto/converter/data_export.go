@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"time"
+
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dataExportTokenVerification = "verification"
+	dataExportTokenReset        = "reset_password"
+)
+
+func DataExportArchiveEntityToDTOResponse(e *entity.DataExportArchive) *dto_response.DataExport {
+	if e == nil {
+		return nil
+	}
+	convert := &dto_response.DataExport{
+		GeneratedAt:     e.GeneratedAt,
+		User:            UserEntityToDTOResponse(e.User),
+		Identities:      ListUserIdentityEntityToDTOResponse(e.Identities),
+		Passkeys:        ListWebAuthnCredentialEntityToDTOResponse(e.Passkeys),
+		Sessions:        ListSessionEntityToDTOResponse(e.Sessions, uuid.Nil),
+		StatusHistory:   ListUserStatusHistoryEntityToDTOResponse(e.StatusHistory),
+		AuditEvents:     ListUserAuditEventEntityToDTOResponse(e.AuditEvents),
+		PasswordChanges: make([]time.Time, 0, len(e.PasswordHistory)),
+		TokenHistory:    make([]dto_response.DataExportToken, 0, len(e.VerificationTokens)+len(e.ResetTokens)),
+	}
+	if e.TOTP != nil {
+		convert.TwoFactor = dto_response.DataExportTwoFactor{
+			Enabled:     e.TOTP.IsEnabled,
+			ConfirmedAt: e.TOTP.ConfirmedAt,
+		}
+	}
+	for _, item := range e.PasswordHistory {
+		convert.PasswordChanges = append(convert.PasswordChanges, item.CreatedAt)
+	}
+	for _, item := range e.VerificationTokens {
+		convert.TokenHistory = append(convert.TokenHistory, dto_response.DataExportToken{
+			Type:          dataExportTokenVerification,
+			CreatedAt:     item.CreatedAt,
+			InvalidatedAt: item.DeletedAt,
+		})
+	}
+	for _, item := range e.ResetTokens {
+		convert.TokenHistory = append(convert.TokenHistory, dto_response.DataExportToken{
+			Type:          dataExportTokenReset,
+			CreatedAt:     item.CreatedAt,
+			InvalidatedAt: item.DeletedAt,
+		})
+	}
+	return convert
+}
+
+func UserStatusHistoryEntityToDTOResponse(e *entity.UserStatusHistory) *dto_response.UserStatusHistory {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.UserStatusHistory{
+		FromStatus:      e.FromStatus,
+		FromStatusLabel: e.FromStatus.String(),
+		ToStatus:        e.ToStatus,
+		ToStatusLabel:   e.ToStatus.String(),
+		Reason:          e.Reason,
+		SuspendedUntil:  e.SuspendedUntil,
+		ActorID:         e.ActorID,
+		CreatedAt:       e.CreatedAt,
+	}
+}
+
+func ListUserStatusHistoryEntityToDTOResponse(e []*entity.UserStatusHistory) []dto_response.UserStatusHistory {
+	result := make([]dto_response.UserStatusHistory, 0, len(e))
+
+	for _, item := range e {
+		if item == nil {
+			continue
+		}
+		dto := UserStatusHistoryEntityToDTOResponse(item)
+		if dto != nil {
+			result = append(result, *dto)
+		}
+	}
+
+	return result
+}
+
+func UserAuditEventEntityToDTOResponse(e *entity.UserAuditEvent) *dto_response.UserAuditEvent {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.UserAuditEvent{
+		Event:     e.Event,
+		Detail:    e.Detail,
+		ActorID:   e.ActorID,
+		ClientIP:  e.ClientIP,
+		UserAgent: e.UserAgent,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func ListUserAuditEventEntityToDTOResponse(e []*entity.UserAuditEvent) []dto_response.UserAuditEvent {
+	result := make([]dto_response.UserAuditEvent, 0, len(e))
+
+	for _, item := range e {
+		if item == nil {
+			continue
+		}
+		dto := UserAuditEventEntityToDTOResponse(item)
+		if dto != nil {
+			result = append(result, *dto)
+		}
+	}
+
+	return result
+}
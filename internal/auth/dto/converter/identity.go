@@ -0,0 +1,44 @@
+package converter
+
+import (
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+)
+
+func UserIdentityEntityToDTOResponse(e *entity.UserIdentity) *dto_response.Identity {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.Identity{
+		ID:         e.ID,
+		Provider:   e.Provider,
+		Email:      e.Email,
+		CreatedAt:  e.CreatedAt,
+		LastUsedAt: e.LastUsedAt,
+	}
+}
+
+func ListUserIdentityEntityToDTOResponse(e []*entity.UserIdentity) []dto_response.Identity {
+	result := make([]dto_response.Identity, 0, len(e))
+
+	for _, item := range e {
+		if item == nil {
+			continue
+		}
+		dto := UserIdentityEntityToDTOResponse(item)
+		if dto != nil {
+			result = append(result, *dto)
+		}
+	}
+
+	return result
+}
+
+func OauthLinkEntityToDTOResponse(e *entity.OauthLink) *dto_response.IdentityLink {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.IdentityLink{
+		AuthURL: e.AuthURL,
+	}
+}
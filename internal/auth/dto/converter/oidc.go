@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+)
+
+func OIDCTokenEntityToDTOResponse(e *entity.OIDCToken, expiresIn int) *dto_response.OIDCToken {
+	if e == nil || e.Session == nil {
+		return nil
+	}
+	return &dto_response.OIDCToken{
+		AccessToken:  e.Session.AccessToken,
+		TokenType:    constantAuth.BEARER_TOKEN_TYPE,
+		ExpiresIn:    expiresIn,
+		RefreshToken: e.Session.RefreshToken,
+		IDToken:      e.IDToken,
+		Scope:        e.Session.Scope,
+	}
+}
+
+// UserEntityToDTOOIDCUserInfo maps a user to the standard OIDC claims allowed by
+// the scope granted to the relying party.
+func UserEntityToDTOOIDCUserInfo(e *entity.User, scope string) *dto_response.OIDCUserInfo {
+	if e == nil {
+		return nil
+	}
+
+	scopes := strings.Fields(scope)
+	allowed := func(s string) bool {
+		return slices.Contains(scopes, s)
+	}
+
+	convert := &dto_response.OIDCUserInfo{
+		Subject: e.ID.String(),
+	}
+
+	if allowed(constantAuth.OIDC_SCOPE_EMAIL) {
+		convert.Email = e.Email
+		emailVerified := e.IsVerified()
+		convert.EmailVerified = &emailVerified
+	}
+
+	if e.UserDetail == nil {
+		return convert
+	}
+
+	if allowed(constantAuth.OIDC_SCOPE_PROFILE) {
+		convert.Name = e.UserDetail.FullName
+		convert.Picture = e.UserDetail.ImageURL
+		convert.Birthdate = e.UserDetail.BirthDate.String()
+
+		switch {
+		case e.UserDetail.Sex.IsSexMale():
+			convert.Gender = "male"
+		case e.UserDetail.Sex.IsSexFemale():
+			convert.Gender = "female"
+		}
+
+		if e.UserDetail.UpdatedAt != nil {
+			convert.UpdatedAt = e.UserDetail.UpdatedAt.Unix()
+		}
+	}
+
+	if allowed(constantAuth.OIDC_SCOPE_PHONE) && e.UserDetail.PhoneNumber != nil {
+		convert.PhoneNumber = *e.UserDetail.PhoneNumber
+	}
+
+	return convert
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+
+	"github.com/google/uuid"
+)
+
+func WebAuthnCeremonyEntityToDTOResponse(e *entity.WebAuthnCeremony) *dto_response.PasskeyCeremony {
+	if e == nil {
+		return nil
+	}
+	convert := &dto_response.PasskeyCeremony{
+		Options: e.Options,
+	}
+	if e.ID != uuid.Nil {
+		convert.CeremonyID = &e.ID
+	}
+	return convert
+}
+
+func WebAuthnCredentialEntityToDTOResponse(e *entity.WebAuthnCredential) *dto_response.Passkey {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.Passkey{
+		ID:         e.ID,
+		Name:       e.Name,
+		CreatedAt:  e.CreatedAt,
+		LastUsedAt: e.LastUsedAt,
+	}
+}
+
+func ListWebAuthnCredentialEntityToDTOResponse(e []*entity.WebAuthnCredential) []dto_response.Passkey {
+	result := make([]dto_response.Passkey, 0, len(e))
+
+	for _, item := range e {
+		if item == nil {
+			continue
+		}
+		dto := WebAuthnCredentialEntityToDTOResponse(item)
+		if dto != nil {
+			result = append(result, *dto)
+		}
+	}
+
+	return result
+}
@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"time"
+
 	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
 	converterPkg "github.com/faisalyudiansah/auth-service-template/pkg/dto/converter"
@@ -10,16 +12,23 @@ func UserEntityToDTOResponse(e *entity.User) *dto_response.User {
 	if e == nil {
 		return nil
 	}
+	status := e.EffectiveStatus(time.Now())
+
 	convert := &dto_response.User{
-		ID:         e.ID,
-		Role:       e.Role,
-		RoleLabel:  e.Role.String(),
-		Email:      e.Email,
-		IsVerified: e.IsVerified,
-		IsOauth:    e.IsOauth,
-		IsActive:   e.IsActive,
-		Audit:      converterPkg.ConvertAudit(e.Audit),
-		UserDetail: UserDetailEntityToDTOResponse(e.UserDetail),
+		ID:          e.ID,
+		Role:        e.Role,
+		RoleLabel:   e.Role.String(),
+		Email:       e.Email,
+		IsVerified:  e.IsVerified(),
+		IsOauth:     e.IsOauth,
+		IsActive:    status.IsUserStatusActive(),
+		Status:      status,
+		StatusLabel: status.String(),
+		Audit:       converterPkg.ConvertAudit(e.Audit),
+		UserDetail:  UserDetailEntityToDTOResponse(e.UserDetail),
+	}
+	if status.IsUserStatusSuspended() {
+		convert.SuspendedUntil = e.SuspendedUntil
 	}
 	return convert
 }
@@ -29,15 +38,16 @@ func UserDetailEntityToDTOResponse(e *entity.UserDetail) *dto_response.UserDetai
 		return nil
 	}
 	convert := &dto_response.UserDetail{
-		ID:          e.ID,
-		UserID:      e.UserID,
-		FullName:    e.FullName,
-		Sex:         e.Sex,
-		SexLabel:    e.Sex.String(),
-		PhoneNumber: e.PhoneNumber,
-		ImageURL:    e.ImageURL,
-		BirthDate:   e.BirthDate,
-		Audit:       converterPkg.ConvertAudit(e.Audit),
+		ID:              e.ID,
+		UserID:          e.UserID,
+		FullName:        e.FullName,
+		Sex:             e.Sex,
+		SexLabel:        e.Sex.String(),
+		PhoneNumber:     e.PhoneNumber,
+		PhoneVerifiedAt: e.PhoneVerifiedAt,
+		ImageURL:        e.ImageURL,
+		BirthDate:       e.BirthDate,
+		Audit:           converterPkg.ConvertAudit(e.Audit),
 	}
 	return convert
 }
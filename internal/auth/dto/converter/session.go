@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"time"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+
+	"github.com/google/uuid"
+)
+
+func SessionEntityToDTOResponse(e *entity.Session, currentSessionID uuid.UUID) *dto_response.Session {
+	if e == nil {
+		return nil
+	}
+	convert := &dto_response.Session{
+		SessionID:   e.SessionID,
+		ClientID:    e.ClientID,
+		LoginMethod: e.LoginMethod,
+		ClientIP:    e.ClientIP,
+		UserAgent:   e.UserAgent,
+		Browser:     e.Browser,
+		OS:          e.OS,
+		DeviceType:  e.DeviceType,
+		LoginAt:     time.UnixMilli(int64(e.LoginAt)),
+		IsCurrent:   e.SessionID == currentSessionID,
+	}
+	if e.LastRefreshAt != 0 {
+		lastRefreshAt := time.UnixMilli(int64(e.LastRefreshAt))
+		convert.LastRefreshAt = &lastRefreshAt
+	}
+	if e.LastSeenAt != 0 {
+		lastSeenAt := time.UnixMilli(int64(e.LastSeenAt))
+		convert.LastSeenAt = &lastSeenAt
+	}
+	return convert
+}
+
+func ListSessionEntityToDTOResponse(e []*entity.Session, currentSessionID uuid.UUID) []dto_response.Session {
+	result := make([]dto_response.Session, 0, len(e))
+
+	for _, item := range e {
+		if item == nil {
+			continue
+		}
+		dto := SessionEntityToDTOResponse(item, currentSessionID)
+		if dto != nil {
+			result = append(result, *dto)
+		}
+	}
+
+	return result
+}
+
+func SessionEntityToDTOToken(e *entity.Session) *dto_response.Token {
+	if e == nil {
+		return nil
+	}
+	return &dto_response.Token{
+		TokenType:    constantAuth.BEARER_TOKEN_TYPE,
+		AccessToken:  e.AccessToken,
+		RefreshToken: e.RefreshToken,
+	}
+}
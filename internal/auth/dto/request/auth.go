@@ -1,6 +1,8 @@
 package dto_request
 
 import (
+	"time"
+
 	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	custom_typePkg "github.com/faisalyudiansah/auth-service-template/pkg/entity/type"
 
@@ -8,8 +10,15 @@ import (
 )
 
 type Login struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	RememberMe bool   `json:"remember_me"`
+
+	ClientIP string `json:"-"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type Register struct {
@@ -42,7 +51,60 @@ type ResetPassword struct {
 	Password   string `json:"password" binding:"required,password"`
 }
 
+type ChangeEmail struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+
+	UserID    uuid.UUID `json:"-"`
+	SessionID uuid.UUID `json:"-"`
+}
+
+type ConfirmEmailChange struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type RequestMagicLink struct {
+	Email      string `json:"email" binding:"required,email"`
+	RememberMe bool   `json:"remember_me"`
+}
+
+type MagicLinkLogin struct {
+	Token string `json:"token" binding:"required"`
+
+	ClientIP string `json:"-"`
+}
+
+type RequestPhoneOTP struct {
+	PhoneNumber string `json:"phone_number" binding:"required,phone_number"`
+	RememberMe  bool   `json:"remember_me"`
+}
+
+type PhoneOTPLogin struct {
+	PhoneNumber string `json:"phone_number" binding:"required,phone_number"`
+	Code        string `json:"code" binding:"required,numeric"`
+}
+
 type InactiveAccount struct {
 	UserID    uuid.UUID `json:"user_id" binding:"required,user_id"`
 	UpdatedBy uuid.UUID `json:"-"`
 }
+
+type SuspendAccount struct {
+	UserID    uuid.UUID  `json:"-"`
+	Reason    string     `json:"reason" binding:"required,max=255"`
+	Until     *time.Time `json:"until"`
+	UpdatedBy uuid.UUID  `json:"-"`
+}
+
+type ActivateAccount struct {
+	UserID    uuid.UUID `json:"-"`
+	UpdatedBy uuid.UUID `json:"-"`
+}
+
+type RequestReactivation struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ConfirmReactivation struct {
+	Token string `json:"token" binding:"required"`
+}
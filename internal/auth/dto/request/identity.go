@@ -0,0 +1,9 @@
+package dto_request
+
+import "github.com/google/uuid"
+
+type LinkIdentity struct {
+	Provider string `json:"provider" binding:"required"`
+
+	UserID uuid.UUID `json:"-"`
+}
@@ -0,0 +1,29 @@
+package dto_request
+
+import (
+	"github.com/google/uuid"
+)
+
+type OIDCAuthorize struct {
+	ResponseType        string `form:"response_type"`
+	ClientID            string `form:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope"`
+	State               string `form:"state"`
+	Nonce               string `form:"nonce"`
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"`
+
+	UserID    uuid.UUID `form:"-"`
+	SessionID uuid.UUID `form:"-"`
+}
+
+type OIDCToken struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
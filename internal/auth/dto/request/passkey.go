@@ -0,0 +1,27 @@
+package dto_request
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type FinishPasskeyRegistration struct {
+	Name       string          `json:"name" binding:"required,max=255"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+
+	UserID uuid.UUID `json:"-"`
+}
+
+type RenamePasskey struct {
+	Name string `json:"name" binding:"required,max=255"`
+
+	ID     uuid.UUID `json:"-"`
+	UserID uuid.UUID `json:"-"`
+}
+
+type FinishPasskeyLogin struct {
+	CeremonyID uuid.UUID       `json:"ceremony_id" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+	RememberMe bool            `json:"remember_me"`
+}
@@ -1,16 +1,19 @@
 package dto_request
 
 import (
+	"time"
+
 	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 
 	"github.com/google/uuid"
 )
 
 type UpdateUser struct {
-	UserID     uuid.UUID         `json:"user_id"`
-	Role       *custom_type.Role `json:"role" binding:"oneof=0 1 2"`
-	IsVerified bool              `json:"is_verified" binding:"boolean"`
-	IsActive   bool              `json:"is_active" binding:"boolean"`
+	UserID         uuid.UUID               `json:"user_id"`
+	Role           *custom_type.Role       `json:"role" binding:"oneof=0 1 2"`
+	Status         *custom_type.UserStatus `json:"status" binding:"omitempty,oneof=1 2 3"`
+	StatusReason   *string                 `json:"status_reason" binding:"omitempty,max=255"`
+	SuspendedUntil *time.Time              `json:"suspended_until"`
 
 	FullName    string           `json:"full_name" binding:"required"`
 	Sex         *custom_type.Sex `json:"sex" binding:"required,oneof=0 1 2"`
@@ -21,9 +24,43 @@ type UpdateUser struct {
 	RoleWhoIsEdit custom_type.Role `json:"-"`
 }
 
+type VerifyPhoneNumber struct {
+	Code string `json:"code" binding:"required,numeric"`
+
+	UserID uuid.UUID `json:"-"`
+}
+
+type ChangePassword struct {
+	CurrentPassword     string `json:"current_password"`
+	NewPassword         string `json:"new_password" binding:"required,password"`
+	RevokeOtherSessions bool   `json:"revoke_other_sessions"`
+
+	UserID    uuid.UUID `json:"-"`
+	SessionID uuid.UUID `json:"-"`
+}
+
+type RequestAccountDeletion struct {
+	Password string `json:"password" binding:"required"`
+
+	UserID uuid.UUID `json:"-"`
+}
+
 type DeleteUser struct {
 	UserID        uuid.UUID `json:"user_id"`
 	DeletedReason string    `json:"deleted_reason" binding:"required"`
 
 	DeletedBy uuid.UUID `json:"-"`
 }
+
+type RequestDataExport struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+
+	UserID      uuid.UUID `json:"-"`
+	RequestedBy uuid.UUID `json:"-"`
+}
+
+type DownloadDataExport struct {
+	Token string `json:"token" binding:"required"`
+
+	UserID uuid.UUID `json:"-"`
+}
@@ -0,0 +1,22 @@
+package dto_request
+
+import (
+	"github.com/google/uuid"
+)
+
+type ConfirmTwoFactor struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+
+	UserID uuid.UUID `json:"-"`
+}
+
+type DisableTwoFactor struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+
+	UserID uuid.UUID `json:"-"`
+}
+
+type LoginTwoFactor struct {
+	ChallengeID uuid.UUID `json:"challenge_id" binding:"required"`
+	Code        string    `json:"code" binding:"required,len=6,numeric"`
+}
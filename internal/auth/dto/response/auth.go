@@ -10,25 +10,44 @@ import (
 )
 
 type Login struct {
-	Email      string               `json:"email"`
-	IsVerified bool                 `json:"is_verified"`
-	IsOauth    bool                 `json:"is_outh"`
-	IsActive   bool                 `json:"is_active"`
-	Role       custom_typeAuth.Role `json:"role"`
-	RoleLabel  string               `json:"role_label"`
+	Email       string                     `json:"email"`
+	IsVerified  bool                       `json:"is_verified"`
+	IsOauth     bool                       `json:"is_outh"`
+	IsActive    bool                       `json:"is_active"`
+	Status      custom_typeAuth.UserStatus `json:"status"`
+	StatusLabel string                     `json:"status_label"`
+	Role        custom_typeAuth.Role       `json:"role"`
+	RoleLabel   string                     `json:"role_label"`
+	Token       *Token                     `json:"token,omitempty"`
+
+	TwoFactorRequired bool            `json:"two_factor_required"`
+	Challenge         *LoginChallenge `json:"challenge,omitempty"`
+}
+
+type LoginChallenge struct {
+	ChallengeID uuid.UUID `json:"challenge_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type Token struct {
+	TokenType    string `json:"token_type"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 type Register struct {
-	ID         uuid.UUID               `json:"id"`
-	Email      string                  `json:"email"`
-	FullName   string                  `json:"full_name"`
-	IsVerified bool                    `json:"is_verified"`
-	IsOauth    bool                    `json:"is_oauth"`
-	IsActive   bool                    `json:"is_active"`
-	Role       custom_typeAuth.Role    `json:"role"`
-	RoleLabel  string                  `json:"role_label"`
-	Sex        custom_typeAuth.Sex     `json:"sex"`
-	SexLabel   string                  `json:"sex_label"`
-	BirthDate  custom_typePkg.DateOnly `json:"birth_date"`
-	CreatedAt  time.Time               `json:"created_at"`
+	ID          uuid.UUID                  `json:"id"`
+	Email       string                     `json:"email"`
+	FullName    string                     `json:"full_name"`
+	IsVerified  bool                       `json:"is_verified"`
+	IsOauth     bool                       `json:"is_oauth"`
+	IsActive    bool                       `json:"is_active"`
+	Status      custom_typeAuth.UserStatus `json:"status"`
+	StatusLabel string                     `json:"status_label"`
+	Role        custom_typeAuth.Role       `json:"role"`
+	RoleLabel   string                     `json:"role_label"`
+	Sex         custom_typeAuth.Sex        `json:"sex"`
+	SexLabel    string                     `json:"sex_label"`
+	BirthDate   custom_typePkg.DateOnly    `json:"birth_date"`
+	CreatedAt   time.Time                  `json:"created_at"`
 }
@@ -0,0 +1,53 @@
+package dto_response
+
+import (
+	"time"
+
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+
+	"github.com/google/uuid"
+)
+
+type DataExport struct {
+	GeneratedAt     time.Time           `json:"generated_at"`
+	User            *User               `json:"user"`
+	Identities      []Identity          `json:"identities"`
+	TwoFactor       DataExportTwoFactor `json:"two_factor"`
+	Passkeys        []Passkey           `json:"passkeys"`
+	Sessions        []Session           `json:"sessions"`
+	StatusHistory   []UserStatusHistory `json:"status_history"`
+	AuditEvents     []UserAuditEvent    `json:"audit_events"`
+	PasswordChanges []time.Time         `json:"password_changes"`
+	TokenHistory    []DataExportToken   `json:"token_history"`
+}
+
+type DataExportTwoFactor struct {
+	Enabled     bool       `json:"enabled"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+}
+
+type UserStatusHistory struct {
+	FromStatus      custom_typeAuth.UserStatus `json:"from_status"`
+	FromStatusLabel string                     `json:"from_status_label"`
+	ToStatus        custom_typeAuth.UserStatus `json:"to_status"`
+	ToStatusLabel   string                     `json:"to_status_label"`
+	Reason          *string                    `json:"reason"`
+	SuspendedUntil  *time.Time                 `json:"suspended_until"`
+	ActorID         uuid.UUID                  `json:"actor_id"`
+	CreatedAt       time.Time                  `json:"created_at"`
+}
+
+type UserAuditEvent struct {
+	Event     string    `json:"event"`
+	Detail    *string   `json:"detail"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	ClientIP  string    `json:"client_ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DataExportToken struct {
+	Type          string     `json:"type"`
+	CreatedAt     time.Time  `json:"created_at"`
+	InvalidatedAt *time.Time `json:"invalidated_at"`
+}
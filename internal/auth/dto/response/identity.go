@@ -0,0 +1,19 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Identity struct {
+	ID         uuid.UUID  `json:"id"`
+	Provider   string     `json:"provider"`
+	Email      string     `json:"email"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+type IdentityLink struct {
+	AuthURL string `json:"auth_url"`
+}
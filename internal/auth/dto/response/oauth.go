@@ -0,0 +1,6 @@
+package dto_response
+
+type OauthProvider struct {
+	Name     string `json:"name"`
+	LoginURL string `json:"login_url"`
+}
@@ -0,0 +1,19 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasskeyCeremony struct {
+	CeremonyID *uuid.UUID `json:"ceremony_id,omitempty"`
+	Options    any        `json:"options"`
+}
+
+type Passkey struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
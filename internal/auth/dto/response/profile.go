@@ -1,6 +1,8 @@
 package dto_response
 
 import (
+	"time"
+
 	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	dtoPkg "github.com/faisalyudiansah/auth-service-template/pkg/dto"
 	custom_typePkg "github.com/faisalyudiansah/auth-service-template/pkg/entity/type"
@@ -9,13 +11,16 @@ import (
 )
 
 type User struct {
-	ID         uuid.UUID            `json:"id"`
-	Role       custom_typeAuth.Role `json:"role"`
-	RoleLabel  string               `json:"role_label"`
-	Email      string               `json:"email"`
-	IsVerified bool                 `json:"is_verified"`
-	IsOauth    bool                 `json:"is_oauth"`
-	IsActive   bool                 `json:"is_active"`
+	ID             uuid.UUID                  `json:"id"`
+	Role           custom_typeAuth.Role       `json:"role"`
+	RoleLabel      string                     `json:"role_label"`
+	Email          string                     `json:"email"`
+	IsVerified     bool                       `json:"is_verified"`
+	IsOauth        bool                       `json:"is_oauth"`
+	IsActive       bool                       `json:"is_active"`
+	Status         custom_typeAuth.UserStatus `json:"status"`
+	StatusLabel    string                     `json:"status_label"`
+	SuspendedUntil *time.Time                 `json:"suspended_until"`
 
 	dtoPkg.Audit
 
@@ -23,14 +28,15 @@ type User struct {
 }
 
 type UserDetail struct {
-	ID          uuid.UUID               `json:"id"`
-	UserID      uuid.UUID               `json:"user_id"`
-	FullName    string                  `json:"full_name"`
-	Sex         custom_typeAuth.Sex     `json:"sex"`
-	SexLabel    string                  `json:"sex_label"`
-	PhoneNumber *string                 `json:"phone_number"`
-	ImageURL    string                  `json:"image_url"`
-	BirthDate   custom_typePkg.DateOnly `json:"birth_date"`
+	ID              uuid.UUID               `json:"id"`
+	UserID          uuid.UUID               `json:"user_id"`
+	FullName        string                  `json:"full_name"`
+	Sex             custom_typeAuth.Sex     `json:"sex"`
+	SexLabel        string                  `json:"sex_label"`
+	PhoneNumber     *string                 `json:"phone_number"`
+	PhoneVerifiedAt *time.Time              `json:"phone_verified_at"`
+	ImageURL        string                  `json:"image_url"`
+	BirthDate       custom_typePkg.DateOnly `json:"birth_date"`
 
 	dtoPkg.Audit
 }
@@ -0,0 +1,22 @@
+package dto_response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	SessionID     uuid.UUID  `json:"session_id"`
+	ClientID      string     `json:"client_id,omitempty"`
+	LoginMethod   string     `json:"login_method"`
+	ClientIP      string     `json:"client_ip"`
+	UserAgent     string     `json:"user_agent"`
+	Browser       string     `json:"browser"`
+	OS            string     `json:"os"`
+	DeviceType    string     `json:"device_type"`
+	LoginAt       time.Time  `json:"login_at"`
+	LastRefreshAt *time.Time `json:"last_refresh_at"`
+	LastSeenAt    *time.Time `json:"last_seen_at"`
+	IsCurrent     bool       `json:"is_current"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExport struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	RequestedBy uuid.UUID `json:"requested_by"`
+	StorageKey  string    `json:"storage_key"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DataExportArchive is everything stored about a user, gathered for a data
+// subject request.
+type DataExportArchive struct {
+	GeneratedAt        time.Time
+	User               *User
+	Identities         []*UserIdentity
+	TOTP               *UserTOTP
+	Passkeys           []*WebAuthnCredential
+	Sessions           []*Session
+	StatusHistory      []*UserStatusHistory
+	AuditEvents        []*UserAuditEvent
+	PasswordHistory    []*PasswordHistory
+	VerificationTokens []*VerificationToken
+	ResetTokens        []*ResetToken
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailChange struct {
+	Token     uuid.UUID `json:"token"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	NewEmail  string    `json:"new_email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MagicLink struct {
+	Token      uuid.UUID `json:"token"`
+	UserID     uuid.UUID `json:"user_id"`
+	RememberMe bool      `json:"remember_me"`
+	CreatedAt  time.Time `json:"created_at"`
+}
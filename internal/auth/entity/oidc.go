@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+type OIDCAuthorizationCode struct {
+	ClientID            string    `json:"client_id"`
+	RedirectURI         string    `json:"redirect_uri"`
+	UserID              uuid.UUID `json:"user_id"`
+	Scope               string    `json:"scope"`
+	Nonce               string    `json:"nonce"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	AuthTime            uint64    `json:"auth_time"`
+	ClientIP            string    `json:"client_ip"`
+	UserAgent           string    `json:"user_agent"`
+}
+
+type OIDCToken struct {
+	Session *Session
+	IDToken string
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordHistory struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	HashPassword string
+	CreatedAt    time.Time
+}
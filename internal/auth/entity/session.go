@@ -1,17 +1,41 @@
 package entity
 
 import (
+	"time"
+
 	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 
 	"github.com/google/uuid"
 )
 
 type Session struct {
-	UserID       uuid.UUID        `json:"user_id"`
-	Role         custom_type.Role `json:"role"`
-	JTI          string           `json:"jti"`
-	SessionID    uuid.UUID        `json:"session_id"`
-	AccessToken  string           `json:"access_token"`
-	RefreshToken string           `json:"refresh_token"`
-	LoginAt      uint64           `json:"login_at"`
+	UserID             uuid.UUID        `json:"user_id"`
+	Role               custom_type.Role `json:"role"`
+	JTI                string           `json:"jti"`
+	SessionID          uuid.UUID        `json:"session_id"`
+	AccessToken        string           `json:"access_token"`
+	RefreshToken       string           `json:"refresh_token"`
+	RefreshJTI         string           `json:"refresh_jti"`
+	PreviousRefreshJTI string           `json:"previous_refresh_jti,omitempty"`
+	ClientID           string           `json:"client_id,omitempty"`
+	Scope              string           `json:"scope,omitempty"`
+	LoginAt            uint64           `json:"login_at"`
+	LastRefreshAt      uint64           `json:"last_refresh_at"`
+	RememberMe         bool             `json:"remember_me"`
+	LoginMethod        string           `json:"login_method"`
+	ClientIP           string           `json:"client_ip"`
+	UserAgent          string           `json:"user_agent"`
+	Browser            string           `json:"browser"`
+	OS                 string           `json:"os"`
+	DeviceType         string           `json:"device_type"`
+	LastSeenAt         uint64           `json:"last_seen_at"`
+
+	// ExpiresAt is when the session drops out of Redis unless it is used again,
+	// it is only filled in by the session usecase and never persisted.
+	ExpiresAt time.Time `json:"-"`
+}
+
+type SessionOptions struct {
+	RememberMe  bool   `json:"remember_me"`
+	LoginMethod string `json:"login_method"`
 }
@@ -0,0 +1,48 @@
+package custom_type
+
+type UserStatus uint
+
+const (
+	UserStatusPendingVerification UserStatus = iota
+	UserStatusActive
+	UserStatusSuspended
+	UserStatusDeactivated
+	UserStatusPendingDeletion
+	UserStatusDeleted
+)
+
+func (s UserStatus) String() string {
+	names := [...]string{"pending_verification", "active", "suspended", "deactivated", "pending_deletion", "deleted"}
+	if int(s) >= len(names) {
+		return "unknown"
+	}
+	return names[s]
+}
+
+func (s UserStatus) EnumIndex() uint {
+	return uint(s)
+}
+
+func (s UserStatus) IsUserStatusPendingVerification() bool {
+	return s == UserStatusPendingVerification
+}
+
+func (s UserStatus) IsUserStatusActive() bool {
+	return s == UserStatusActive
+}
+
+func (s UserStatus) IsUserStatusSuspended() bool {
+	return s == UserStatusSuspended
+}
+
+func (s UserStatus) IsUserStatusDeactivated() bool {
+	return s == UserStatusDeactivated
+}
+
+func (s UserStatus) IsUserStatusPendingDeletion() bool {
+	return s == UserStatusPendingDeletion
+}
+
+func (s UserStatus) IsUserStatusDeleted() bool {
+	return s == UserStatusDeleted
+}
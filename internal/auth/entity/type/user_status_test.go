@@ -0,0 +1,22 @@
+package custom_type
+
+import "testing"
+
+func TestUserStatus_String(t *testing.T) {
+	tests := []struct {
+		status UserStatus
+		want   string
+	}{
+		{status: UserStatusPendingVerification, want: "pending_verification"},
+		{status: UserStatusActive, want: "active"},
+		{status: UserStatusDeleted, want: "deleted"},
+		{status: UserStatusDeleted + 1, want: "unknown"},
+		{status: UserStatus(255), want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.String(); got != tt.want {
+			t.Errorf("UserStatus(%d).String() = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	entityPkg "github.com/faisalyudiansah/auth-service-template/pkg/entity"
 
@@ -8,15 +10,31 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID
-	Role         custom_type.Role
-	Email        string
-	HashPassword string
-	IsVerified   bool
-	IsOauth      bool
-	IsActive     bool
+	ID              uuid.UUID
+	Role            custom_type.Role
+	Email           string
+	HashPassword    string
+	IsOauth         bool
+	Status          custom_type.UserStatus
+	StatusReason    *string
+	SuspendedUntil  *time.Time
+	StatusChangedAt *time.Time
+	StatusChangedBy *uuid.UUID
 
 	entityPkg.Audit
 
 	UserDetail *UserDetail
 }
+
+func (u *User) IsVerified() bool {
+	return !u.Status.IsUserStatusPendingVerification()
+}
+
+// EffectiveStatus reads a suspension whose end has passed as active, the
+// stored status is only moved on by the next transition.
+func (u *User) EffectiveStatus(now time.Time) custom_type.UserStatus {
+	if u.Status.IsUserStatusSuspended() && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+		return custom_type.UserStatusActive
+	}
+	return u.Status
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserAuditEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Event     string
+	Detail    *string
+	ActorID   uuid.UUID
+	ClientIP  string
+	UserAgent string
+	CreatedAt time.Time
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	entityPkg "github.com/faisalyudiansah/auth-service-template/pkg/entity"
 	custom_typePkg "github.com/faisalyudiansah/auth-service-template/pkg/entity/type"
@@ -9,13 +11,14 @@ import (
 )
 
 type UserDetail struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	FullName    string
-	Sex         custom_typeAuth.Sex
-	PhoneNumber *string
-	ImageURL    string
-	BirthDate   custom_typePkg.DateOnly
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	FullName        string
+	Sex             custom_typeAuth.Sex
+	PhoneNumber     *string
+	PhoneVerifiedAt *time.Time
+	ImageURL        string
+	BirthDate       custom_typePkg.DateOnly
 
 	entityPkg.Audit
 }
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentity struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Provider       string
+	ProviderUserID string
+	Email          string
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      *time.Time
+}
+
+type OauthLink struct {
+	State     uuid.UUID `json:"state"`
+	UserID    uuid.UUID `json:"user_id"`
+	Provider  string    `json:"provider"`
+	CreatedAt time.Time `json:"created_at"`
+
+	AuthURL string `json:"-"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+
+	"github.com/google/uuid"
+)
+
+type UserStatusHistory struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	FromStatus     custom_type.UserStatus
+	ToStatus       custom_type.UserStatus
+	Reason         *string
+	SuspendedUntil *time.Time
+	ActorID        uuid.UUID
+	CreatedAt      time.Time
+}
+
+type StatusChange struct {
+	To             custom_type.UserStatus
+	ActorID        uuid.UUID
+	Reason         *string
+	SuspendedUntil *time.Time
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTOTP struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Secret       string
+	IsEnabled    bool
+	LastUsedStep int64
+	ConfirmedAt  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    *time.Time
+}
+
+type TOTPEnrollment struct {
+	Secret     string
+	OtpauthURI string
+	QRCode     string
+}
+
+type LoginChallenge struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	SessionOptions SessionOptions `json:"session_options"`
+}
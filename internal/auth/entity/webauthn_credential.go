@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebAuthnCredential struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CredentialID []byte
+	Name         string
+	Credential   []byte
+	LastUsedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    *time.Time
+}
+
+type WebAuthnCeremony struct {
+	ID      uuid.UUID
+	Options any
+}
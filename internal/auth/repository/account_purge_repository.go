@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+// purgeUserDataQueries removes every row that holds personal data of a user,
+// a table gaining a user_id column belongs here.
+var purgeUserDataQueries = []string{
+	`delete from user_details where user_id = $1`,
+	`delete from token_verification_users where user_id = $1`,
+	`delete from token_reset_users where user_id = $1`,
+	`delete from user_totps where user_id = $1`,
+	`delete from user_webauthn_credentials where user_id = $1`,
+	`delete from password_history where user_id = $1`,
+	`delete from user_identities where user_id = $1`,
+	`delete from user_audit_events where user_id = $1`,
+}
+
+type AccountPurgeRepository interface {
+	FindDue(ctx context.Context, requestedBefore time.Time, limit int) ([]uuid.UUID, error)
+	ClaimDue(ctx context.Context, userID uuid.UUID, requestedBefore time.Time) (bool, error)
+	Purge(ctx context.Context, userID uuid.UUID, reason string) error
+}
+
+type accountPurgeRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewAccountPurgeRepository(db database.Executor) *accountPurgeRepositoryImpl {
+	return &accountPurgeRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *accountPurgeRepositoryImpl) FindDue(ctx context.Context, requestedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id
+		from users
+		where status = 4 and status_changed_at <= $1 and deleted_at is null
+		order by status_changed_at
+		limit $2
+	`
+
+	rows, err := db(ctx, query, requestedBefore, limit)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+// ClaimDue locks the users row of an account that is still due for purging
+// until the surrounding transaction ends. Rows another instance of the job
+// holds are skipped, so is an account that was reactivated or purged since
+// FindDue listed it.
+func (r *accountPurgeRepositoryImpl) ClaimDue(ctx context.Context, userID uuid.UUID, requestedBefore time.Time) (bool, error) {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		select id
+		from users
+		where id = $1 and status = 4 and status_changed_at <= $2 and deleted_at is null
+		for update skip locked
+	`
+
+	var id uuid.UUID
+	if err := db(ctx, query, userID, requestedBefore).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, apperrorPkg.NewServerError(err)
+	}
+
+	return true, nil
+}
+
+// Purge deletes the personal data of a user and anonymizes the users row,
+// which stays behind as a tombstone next to its status history.
+func (r *accountPurgeRepositoryImpl) Purge(ctx context.Context, userID uuid.UUID, reason string) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	for _, query := range purgeUserDataQueries {
+		if _, err := db(ctx, query, userID); err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+	}
+
+	query := `
+		update users set
+			email = '',
+			hash_password = '',
+			status_reason = null,
+			purged_at = now(),
+			updated_at = now(),
+			deleted_at = coalesce(deleted_at, now()),
+			deleted_by = coalesce(deleted_by, $1),
+			deleted_reason = $2
+		where id = $3
+	`
+
+	if _, err := db(ctx, query, userID.String(), reason, userID); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
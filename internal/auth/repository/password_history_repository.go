@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+type PasswordHistoryRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PasswordHistory, error)
+	FindLatestByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*entity.PasswordHistory, error)
+	Save(ctx context.Context, history *entity.PasswordHistory) error
+	ClearExceptLatest(ctx context.Context, userID uuid.UUID, keep int) error
+}
+
+type passwordHistoryRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewPasswordHistoryRepository(db database.Executor) *passwordHistoryRepositoryImpl {
+	return &passwordHistoryRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindByUserID lists every password change of a user, cleared hashes
+// included.
+func (r *passwordHistoryRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.PasswordHistory, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, user_id, hash_password, created_at
+		from password_history
+		where user_id = $1
+		order by created_at desc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.PasswordHistory, 0)
+
+	for rows.Next() {
+		item := &entity.PasswordHistory{}
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.HashPassword,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+// FindLatestByUserID only returns entries that still hold their hash.
+func (r *passwordHistoryRepositoryImpl) FindLatestByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*entity.PasswordHistory, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, user_id, hash_password, created_at
+		from password_history
+		where user_id = $1 and hash_password <> ''
+		order by created_at desc
+		limit $2
+	`
+
+	rows, err := db(ctx, query, userID, limit)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.PasswordHistory, 0)
+
+	for rows.Next() {
+		item := &entity.PasswordHistory{}
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.HashPassword,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+func (r *passwordHistoryRepositoryImpl) Save(ctx context.Context, history *entity.PasswordHistory) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		insert into password_history (user_id, hash_password)
+		values ($1, $2)
+		returning id, created_at
+	`
+
+	if err := db(ctx, query, history.UserID, history.HashPassword).Scan(&history.ID, &history.CreatedAt); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+// ClearExceptLatest drops the hashes that fell out of the history but keeps
+// their rows, when a password changed stays part of the account's data.
+func (r *passwordHistoryRepositoryImpl) ClearExceptLatest(ctx context.Context, userID uuid.UUID, keep int) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update password_history set hash_password = ''
+		where user_id = $1 and hash_password <> '' and id not in (
+			select id from password_history
+			where user_id = $1 and hash_password <> ''
+			order by created_at desc
+			limit $2
+		)
+	`
+
+	if _, err := db(ctx, query, userID, keep); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
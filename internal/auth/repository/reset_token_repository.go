@@ -15,6 +15,7 @@ import (
 
 type ResetTokenRepository interface {
 	FindByResetToken(ctx context.Context, token, userID uuid.UUID) (*entity.ResetToken, error)
+	FindHistoryByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.ResetToken, error)
 	Save(ctx context.Context, resetToken *entity.ResetToken) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
@@ -98,3 +99,41 @@ func (r *resetTokenRepositoryImpl) DeleteByUserID(ctx context.Context, userID uu
 
 	return nil
 }
+
+// FindHistoryByUserID also returns used and expired tokens, newest first.
+func (r *resetTokenRepositoryImpl) FindHistoryByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.ResetToken, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, created_at, updated_at, deleted_at from token_reset_users where user_id = $1 order by created_at desc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.ResetToken, 0)
+
+	for rows.Next() {
+		item := &entity.ResetToken{
+			UserID: userID,
+		}
+
+		if err := rows.Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	Find(ctx context.Context, sessionID uuid.UUID) (*entity.Session, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error)
+	Save(ctx context.Context, session *entity.Session, duration time.Duration) error
+	Rotate(ctx context.Context, session *entity.Session, previousRefreshJTI string, duration time.Duration) (bool, error)
+	Delete(ctx context.Context, session *entity.Session) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type sessionRepositoryImpl struct {
+	redisUtil redisutils.RedisUtil
+}
+
+func NewSessionRepository(redisUtil redisutils.RedisUtil) *sessionRepositoryImpl {
+	return &sessionRepositoryImpl{
+		redisUtil: redisUtil,
+	}
+}
+
+func (r *sessionRepositoryImpl) Find(ctx context.Context, sessionID uuid.UUID) (*entity.Session, error) {
+	session := &entity.Session{}
+
+	if err := r.redisUtil.GetWithScanJSON(ctx, utils.SessionKey(sessionID), session); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	if session.UserID == uuid.Nil {
+		return nil, nil
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error) {
+	indexKey := utils.UserSessionsKey(userID)
+
+	members, err := r.redisUtil.SMembers(ctx, indexKey)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	sessions := make([]*entity.Session, 0, len(members))
+	staleMembers := []string{}
+
+	for _, member := range members {
+		sessionID, err := uuid.Parse(member)
+		if err != nil {
+			staleMembers = append(staleMembers, member)
+			continue
+		}
+
+		session, err := r.Find(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if session == nil || session.UserID != userID {
+			staleMembers = append(staleMembers, member)
+			continue
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if len(staleMembers) > 0 {
+		if err := r.redisUtil.SRem(ctx, indexKey, staleMembers...); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LoginAt > sessions[j].LoginAt
+	})
+
+	return sessions, nil
+}
+
+func (r *sessionRepositoryImpl) Save(ctx context.Context, session *entity.Session, duration time.Duration) error {
+	// the session and its index entry are written together, revoking all
+	// sessions of the user in between would otherwise leave this one behind
+	if err := r.redisUtil.SetWithIndex(ctx, utils.SessionKey(session.SessionID), session, duration, utils.UserSessionsKey(session.UserID), session.SessionID.String()); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+// Rotate saves the session only while it still holds previousRefreshJTI, it
+// reports false when another refresh got there first.
+func (r *sessionRepositoryImpl) Rotate(ctx context.Context, session *entity.Session, previousRefreshJTI string, duration time.Duration) (bool, error) {
+	rotated, err := r.redisUtil.CompareAndSetJSON(ctx, utils.SessionKey(session.SessionID), "refresh_jti", previousRefreshJTI, session, duration)
+	if err != nil {
+		return false, apperrorPkg.NewServerError(err)
+	}
+
+	if !rotated {
+		return false, nil
+	}
+
+	return true, r.extendIndex(ctx, session.UserID, duration)
+}
+
+// extendIndex keeps the per-user index alive for at least duration, it never
+// shortens it because other sessions of the user may live longer.
+func (r *sessionRepositoryImpl) extendIndex(ctx context.Context, userID uuid.UUID, duration time.Duration) error {
+	indexKey := utils.UserSessionsKey(userID)
+
+	indexTTL, err := r.redisUtil.TTL(ctx, indexKey)
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if indexTTL >= duration {
+		return nil
+	}
+
+	if err := r.redisUtil.Expire(ctx, indexKey, duration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepositoryImpl) Delete(ctx context.Context, session *entity.Session) error {
+	if err := r.redisUtil.Delete(ctx, utils.SessionKey(session.SessionID)); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if err := r.redisUtil.SRem(ctx, utils.UserSessionsKey(session.UserID), session.SessionID.String()); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+// DeleteByUserID reads the index and deletes it with every session it names
+// in one step, a session saved meanwhile cannot slip between the two.
+func (r *sessionRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := r.redisUtil.DeleteIndex(ctx, utils.UserSessionsKey(userID), utils.SessionKeyPrefix()); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+type UserAuditEventRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserAuditEvent, error)
+	Save(ctx context.Context, event *entity.UserAuditEvent) error
+}
+
+type userAuditEventRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewUserAuditEventRepository(db database.Executor) *userAuditEventRepositoryImpl {
+	return &userAuditEventRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *userAuditEventRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserAuditEvent, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, user_id, event, detail, actor_id, client_ip, user_agent, created_at
+		from user_audit_events
+		where user_id = $1
+		order by created_at desc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.UserAuditEvent, 0)
+
+	for rows.Next() {
+		item := &entity.UserAuditEvent{}
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Event,
+			&item.Detail,
+			&item.ActorID,
+			&item.ClientIP,
+			&item.UserAgent,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+func (r *userAuditEventRepositoryImpl) Save(ctx context.Context, event *entity.UserAuditEvent) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		insert into user_audit_events (user_id, event, detail, actor_id, client_ip, user_agent)
+		values ($1, $2, $3, $4, $5, $6)
+		returning id, created_at
+	`
+
+	if err := db(
+		ctx,
+		query,
+		event.UserID,
+		event.Event,
+		event.Detail,
+		event.ActorID,
+		event.ClientIP,
+		event.UserAgent,
+	).Scan(&event.ID, &event.CreatedAt); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
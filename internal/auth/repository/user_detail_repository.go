@@ -39,7 +39,7 @@ func (r *userDetailRepositoryImpl) Find(ctx context.Context, field string, value
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, user_id, full_name, sex, phone_number, image_url, birth_date, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by
+		SELECT id, user_id, full_name, sex, phone_number, phone_verified_at, image_url, birth_date, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by
 		FROM user_details
 		WHERE %s = $1 AND deleted_at IS NULL
 	`, field)
@@ -52,6 +52,7 @@ func (r *userDetailRepositoryImpl) Find(ctx context.Context, field string, value
 		&userDetail.FullName,
 		&userDetail.Sex,
 		&userDetail.PhoneNumber,
+		&userDetail.PhoneVerifiedAt,
 		&userDetail.ImageURL,
 		&userDetail.BirthDate,
 		&userDetail.CreatedAt,
@@ -80,7 +81,7 @@ func (r *userDetailRepositoryImpl) Save(ctx context.Context, userDetail *entity.
 	query := `
 		INSERT INTO user_details (user_id, full_name, sex, phone_number, image_url, birth_date, created_at, created_by) VALUES 
 		($1, $2, $3, NULL, $4, $5, NOW(), $6)
-		RETURNING id, user_id, full_name, sex, phone_number, image_url, birth_date, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by;
+		RETURNING id, user_id, full_name, sex, phone_number, phone_verified_at, image_url, birth_date, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by;
 	`
 	defaultImgUrl := r.cfgConfig.App.DefaultImageUserProfile
 
@@ -92,6 +93,7 @@ func (r *userDetailRepositoryImpl) Save(ctx context.Context, userDetail *entity.
 		&resUserDetail.FullName,
 		&resUserDetail.Sex,
 		&resUserDetail.PhoneNumber,
+		&resUserDetail.PhoneVerifiedAt,
 		&resUserDetail.ImageURL,
 		&resUserDetail.BirthDate,
 		&resUserDetail.CreatedAt,
@@ -122,19 +124,21 @@ func (r *userDetailRepositoryImpl) Update(ctx context.Context, userDetail *entit
 			full_name = $1,
 			sex = $2,
 			phone_number = $3,
-			image_url = $4,
-			birth_date = $5
+			phone_verified_at = $4,
+			image_url = $5,
+			birth_date = $6
 	`
 
 	args := []any{
 		userDetail.FullName,
 		userDetail.Sex,
 		userDetail.PhoneNumber,
+		userDetail.PhoneVerifiedAt,
 		userDetail.ImageURL,
 		userDetail.BirthDate,
 	}
 
-	argIdx := 6
+	argIdx := 7
 
 	if userDetail.UpdatedBy != nil && userDetail.DeletedBy == nil {
 		query += `
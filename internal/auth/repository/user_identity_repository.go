@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentityRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error)
+	FindByID(ctx context.Context, id, userID uuid.UUID) (*entity.UserIdentity, error)
+	FindByProvider(ctx context.Context, provider, providerUserID string) (*entity.UserIdentity, error)
+	FindUnclaimedByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserIdentity, error)
+	Save(ctx context.Context, identity *entity.UserIdentity) error
+	Claim(ctx context.Context, identity *entity.UserIdentity) (bool, error)
+	UpdateLastUsedAt(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type userIdentityRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewUserIdentityRepository(db database.Executor) *userIdentityRepositoryImpl {
+	return &userIdentityRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *userIdentityRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, user_id, provider, coalesce(provider_user_id, ''), email, last_used_at, created_at, updated_at
+		from user_identities
+		where user_id = $1 and deleted_at is null
+		order by created_at asc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.UserIdentity, 0)
+
+	for rows.Next() {
+		item := &entity.UserIdentity{}
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Provider,
+			&item.ProviderUserID,
+			&item.Email,
+			&item.LastUsedAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+func (r *userIdentityRepositoryImpl) FindByID(ctx context.Context, id, userID uuid.UUID) (*entity.UserIdentity, error) {
+	return r.findOne(ctx, "id = $1 and user_id = $2", id, userID)
+}
+
+func (r *userIdentityRepositoryImpl) FindByProvider(ctx context.Context, provider, providerUserID string) (*entity.UserIdentity, error) {
+	return r.findOne(ctx, "provider = $1 and provider_user_id = $2", provider, providerUserID)
+}
+
+// FindUnclaimedByUserID finds the identity backfilled for an oauth account
+// created before identities were recorded, it has no provider user id yet.
+func (r *userIdentityRepositoryImpl) FindUnclaimedByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserIdentity, error) {
+	return r.findOne(ctx, "user_id = $1 and provider_user_id is null", userID)
+}
+
+func (r *userIdentityRepositoryImpl) findOne(ctx context.Context, where string, args ...any) (*entity.UserIdentity, error) {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		select id, user_id, provider, coalesce(provider_user_id, ''), email, last_used_at, created_at, updated_at
+		from user_identities
+		where ` + where + ` and deleted_at is null
+	`
+
+	item := &entity.UserIdentity{}
+
+	if err := db(ctx, query, args...).Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Provider,
+		&item.ProviderUserID,
+		&item.Email,
+		&item.LastUsedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return item, nil
+}
+
+func (r *userIdentityRepositoryImpl) Save(ctx context.Context, identity *entity.UserIdentity) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		insert into user_identities (user_id, provider, provider_user_id, email, last_used_at)
+		values ($1, $2, $3, $4, $5)
+		returning id, created_at
+	`
+
+	if err := db(
+		ctx,
+		query,
+		identity.UserID,
+		identity.Provider,
+		identity.ProviderUserID,
+		identity.Email,
+		identity.LastUsedAt,
+	).Scan(&identity.ID, &identity.CreatedAt); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+// Claim sets the provider user id of an unclaimed identity. It reports false
+// when the identity was claimed or removed in the meantime.
+func (r *userIdentityRepositoryImpl) Claim(ctx context.Context, identity *entity.UserIdentity) (bool, error) {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_identities set provider_user_id = $1, email = $2, last_used_at = now(), updated_at = now()
+		where id = $3 and provider_user_id is null and deleted_at is null
+	`
+
+	result, err := db(ctx, query, identity.ProviderUserID, identity.Email, identity.ID)
+	if err != nil {
+		return false, apperrorPkg.NewServerError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, apperrorPkg.NewServerError(err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *userIdentityRepositoryImpl) UpdateLastUsedAt(ctx context.Context, id uuid.UUID) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_identities set last_used_at = now()
+		where id = $1 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, id); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *userIdentityRepositoryImpl) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_identities set updated_at = now(), deleted_at = now()
+		where id = $1 and user_id = $2 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, id, userID); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
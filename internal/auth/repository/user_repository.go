@@ -23,9 +23,12 @@ type UserRepository interface {
 	GetListUserWithDetail(ctx context.Context, req *dtoPkg.ListRequest) ([]*entity.User, error)
 	GetTotalCount(ctx context.Context, req *dtoPkg.ListRequest) (uint64, error)
 	Find(ctx context.Context, field string, value any) (*entity.User, error)
+	LockByID(ctx context.Context, userID uuid.UUID) error
 	Save(ctx context.Context, user *entity.User) (*entity.User, error)
 	SaveOauth(ctx context.Context, user *entity.User) error
 	UpdatePassword(ctx context.Context, user *entity.User) error
+	UpdateEmail(ctx context.Context, user *entity.User) error
+	UpdateStatus(ctx context.Context, user *entity.User) error
 	Update(ctx context.Context, user *entity.User) error
 }
 
@@ -43,9 +46,10 @@ var (
 	userAllowedFilters = map[string]string{
 		"email":        "u.email",
 		"role":         "u.role",
-		"is_verified":  "u.is_verified",
+		"is_verified":  "(u.status <> 0)",
 		"is_oauth":     "u.is_oauth",
-		"is_active":    "u.is_active",
+		"is_active":    "(u.status = 1 or (u.status = 2 and u.suspended_until <= now()))",
+		"status":       "u.status",
 		"created_at":   "u.created_at",
 		"full_name":    "ud.full_name",
 		"sex":          "ud.sex",
@@ -72,9 +76,9 @@ func (r *userRepositoryImpl) GetListUserWithDetail(ctx context.Context, req *dto
 			u.id,
 			u.role,
 			u.email,
-			u.is_verified,
 			u.is_oauth,
-			u.is_active,
+			u.status,
+			u.suspended_until,
 			u.created_at,
 			u.created_by,
 			u.updated_at,
@@ -87,6 +91,7 @@ func (r *userRepositoryImpl) GetListUserWithDetail(ctx context.Context, req *dto
 			ud.full_name,
 			ud.sex,
 			ud.phone_number,
+			ud.phone_verified_at,
 			ud.image_url,
 			ud.birth_date,
 			ud.created_at,
@@ -139,6 +144,7 @@ func (r *userRepositoryImpl) GetListUserWithDetail(ctx context.Context, req *dto
 			fullName    sql.NullString
 			sex         sql.NullInt16
 			phoneNumber sql.NullString
+			phoneVerAt  sql.NullTime
 			imageURL    sql.NullString
 			birthDate   sql.NullTime
 			udCreatedAt sql.NullTime
@@ -153,9 +159,9 @@ func (r *userRepositoryImpl) GetListUserWithDetail(ctx context.Context, req *dto
 			&item.ID,
 			&item.Role,
 			&item.Email,
-			&item.IsVerified,
 			&item.IsOauth,
-			&item.IsActive,
+			&item.Status,
+			&item.SuspendedUntil,
 			&item.CreatedAt,
 			&item.CreatedBy,
 			&item.UpdatedAt,
@@ -168,6 +174,7 @@ func (r *userRepositoryImpl) GetListUserWithDetail(ctx context.Context, req *dto
 			&fullName,
 			&sex,
 			&phoneNumber,
+			&phoneVerAt,
 			&imageURL,
 			&birthDate,
 			&udCreatedAt,
@@ -198,6 +205,10 @@ func (r *userRepositoryImpl) GetListUserWithDetail(ctx context.Context, req *dto
 				item.UserDetail.PhoneNumber = &phoneNumber.String
 			}
 
+			if phoneVerAt.Valid {
+				item.UserDetail.PhoneVerifiedAt = &phoneVerAt.Time
+			}
+
 			if udUpdatedAt.Valid {
 				item.UserDetail.UpdatedAt = &udUpdatedAt.Time
 			}
@@ -269,7 +280,7 @@ func (r *userRepositoryImpl) Find(ctx context.Context, field string, value any)
 	}
 
 	query := fmt.Sprintf(`
-		select id, role, email, hash_password, is_verified, is_oauth, is_active, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by 
+		select id, role, email, hash_password, is_oauth, status, status_reason, suspended_until, status_changed_at, status_changed_by, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by 
 		from users 
 		where %s = $1 and deleted_at is null
 	`, field)
@@ -281,9 +292,12 @@ func (r *userRepositoryImpl) Find(ctx context.Context, field string, value any)
 		&user.Role,
 		&user.Email,
 		&user.HashPassword,
-		&user.IsVerified,
 		&user.IsOauth,
-		&user.IsActive,
+		&user.Status,
+		&user.StatusReason,
+		&user.SuspendedUntil,
+		&user.StatusChangedAt,
+		&user.StatusChangedBy,
 		&user.CreatedAt,
 		&user.CreatedBy,
 		&user.UpdatedAt,
@@ -299,6 +313,29 @@ func (r *userRepositoryImpl) Find(ctx context.Context, field string, value any)
 	return user, nil
 }
 
+// LockByID locks the users row until the surrounding transaction ends, so
+// changes that depend on more than the row itself are serialized per user.
+func (r *userRepositoryImpl) LockByID(ctx context.Context, userID uuid.UUID) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		select id from users where id = $1 and deleted_at is null for update
+	`
+
+	var id uuid.UUID
+	if err := db(ctx, query, userID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrorPkg.NewEntityNotFoundError("user")
+		}
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
 func (r *userRepositoryImpl) Save(ctx context.Context, user *entity.User) (*entity.User, error) {
 	db := r.db.QueryRowContext
 	if tx := transactor.ExtractTx(ctx); tx != nil {
@@ -306,21 +343,20 @@ func (r *userRepositoryImpl) Save(ctx context.Context, user *entity.User) (*enti
 	}
 
 	query := `
-		INSERT INTO users (id, role, email, hash_password, is_verified, created_at, created_by) VALUES 
+		INSERT INTO users (id, role, email, hash_password, status, created_at, created_by) VALUES 
 		($1, $2, $3, $4, $5, NOW(), $6)
-		RETURNING id, role, email, hash_password, is_verified, is_oauth, is_active, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by;
+		RETURNING id, role, email, hash_password, is_oauth, status, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by;
 	`
 
 	var resUser entity.User
 
-	if err := db(ctx, query, user.ID, user.Role, user.Email, user.HashPassword, user.IsVerified, user.CreatedBy).Scan(
+	if err := db(ctx, query, user.ID, user.Role, user.Email, user.HashPassword, user.Status, user.CreatedBy).Scan(
 		&resUser.ID,
 		&resUser.Role,
 		&resUser.Email,
 		&resUser.HashPassword,
-		&resUser.IsVerified,
 		&resUser.IsOauth,
-		&resUser.IsActive,
+		&resUser.Status,
 		&resUser.CreatedAt,
 		&resUser.CreatedBy,
 		&resUser.UpdatedAt,
@@ -343,9 +379,9 @@ func (r *userRepositoryImpl) SaveOauth(ctx context.Context, user *entity.User) e
 	id := uuid.New()
 
 	query := `
-		INSERT INTO users (id, role, email, is_verified, is_oauth, created_at, created_by) VALUES 
-		($1, 1, $2, true, true, now(), $3)
-		RETURNING id, role, email, hash_password, is_verified, is_oauth, is_active, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by;
+		INSERT INTO users (id, role, email, status, is_oauth, created_at, created_by) VALUES 
+		($1, 1, $2, 1, true, now(), $3)
+		RETURNING id, role, email, hash_password, is_oauth, status, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by;
 	`
 
 	if err := db(ctx, query, id, user.Email, id).Scan(
@@ -353,9 +389,8 @@ func (r *userRepositoryImpl) SaveOauth(ctx context.Context, user *entity.User) e
 		&user.Role,
 		&user.Email,
 		&user.HashPassword,
-		&user.IsVerified,
 		&user.IsOauth,
-		&user.IsActive,
+		&user.Status,
 		&user.CreatedAt,
 		&user.CreatedBy,
 		&user.UpdatedAt,
@@ -386,6 +421,67 @@ func (r *userRepositoryImpl) UpdatePassword(ctx context.Context, user *entity.Us
 	return nil
 }
 
+func (r *userRepositoryImpl) UpdateEmail(ctx context.Context, user *entity.User) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update users set email = $1, updated_at = now(), updated_by = $2 where id = $3 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, user.Email, user.UpdatedBy, user.ID); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *userRepositoryImpl) UpdateStatus(ctx context.Context, user *entity.User) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update users set
+			status = $1,
+			status_reason = $2,
+			suspended_until = $3,
+			status_changed_at = $4,
+			status_changed_by = $5,
+			updated_at = now(),
+			updated_by = $5
+		where id = $6 and deleted_at is null
+	`
+
+	result, err := db(
+		ctx,
+		query,
+		user.Status,
+		user.StatusReason,
+		user.SuspendedUntil,
+		user.StatusChangedAt,
+		user.StatusChangedBy,
+		user.ID,
+	)
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrorPkg.NewEntityNotFoundError("user")
+	}
+
+	return nil
+}
+
 func (r *userRepositoryImpl) Update(ctx context.Context, user *entity.User) error {
 	db := r.db.QueryRowContext
 	if tx := transactor.ExtractTx(ctx); tx != nil {
@@ -395,18 +491,14 @@ func (r *userRepositoryImpl) Update(ctx context.Context, user *entity.User) erro
 	query := `
 		UPDATE users
 		SET
-			role = $1,
-			is_verified = $2,
-			is_active = $3
+			role = $1
 	`
 
 	args := []any{
 		user.Role,
-		user.IsVerified,
-		user.IsActive,
 	}
 
-	argIdx := 4
+	argIdx := 2
 
 	if user.UpdatedBy != nil && user.DeletedBy == nil {
 		query += `
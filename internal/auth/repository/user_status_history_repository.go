@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+type UserStatusHistoryRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserStatusHistory, error)
+	Save(ctx context.Context, history *entity.UserStatusHistory) error
+}
+
+type userStatusHistoryRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewUserStatusHistoryRepository(db database.Executor) *userStatusHistoryRepositoryImpl {
+	return &userStatusHistoryRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *userStatusHistoryRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserStatusHistory, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, user_id, from_status, to_status, reason, suspended_until, actor_id, created_at
+		from user_status_history
+		where user_id = $1
+		order by created_at desc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.UserStatusHistory, 0)
+
+	for rows.Next() {
+		item := &entity.UserStatusHistory{}
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.FromStatus,
+			&item.ToStatus,
+			&item.Reason,
+			&item.SuspendedUntil,
+			&item.ActorID,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+func (r *userStatusHistoryRepositoryImpl) Save(ctx context.Context, history *entity.UserStatusHistory) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		insert into user_status_history (user_id, from_status, to_status, reason, suspended_until, actor_id)
+		values ($1, $2, $3, $4, $5, $6)
+		returning id, created_at
+	`
+
+	if err := db(
+		ctx,
+		query,
+		history.UserID,
+		history.FromStatus,
+		history.ToStatus,
+		history.Reason,
+		history.SuspendedUntil,
+		history.ActorID,
+	).Scan(&history.ID, &history.CreatedAt); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+type UserTOTPRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error)
+	Save(ctx context.Context, userTOTP *entity.UserTOTP) error
+	Enable(ctx context.Context, userTOTP *entity.UserTOTP) error
+	UpdateLastUsedStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type userTOTPRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewUserTOTPRepository(db database.Executor) *userTOTPRepositoryImpl {
+	return &userTOTPRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *userTOTPRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error) {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		select id, user_id, secret, is_enabled, last_used_step, confirmed_at, created_at, updated_at
+		from user_totps
+		where user_id = $1 and deleted_at is null
+	`
+
+	userTOTP := &entity.UserTOTP{}
+
+	if err := db(ctx, query, userID).Scan(
+		&userTOTP.ID,
+		&userTOTP.UserID,
+		&userTOTP.Secret,
+		&userTOTP.IsEnabled,
+		&userTOTP.LastUsedStep,
+		&userTOTP.ConfirmedAt,
+		&userTOTP.CreatedAt,
+		&userTOTP.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return userTOTP, nil
+}
+
+func (r *userTOTPRepositoryImpl) Save(ctx context.Context, userTOTP *entity.UserTOTP) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		insert into user_totps (user_id, secret) values ($1, $2) returning id, created_at
+	`
+
+	if err := db(ctx, query, userTOTP.UserID, userTOTP.Secret).Scan(&userTOTP.ID, &userTOTP.CreatedAt); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *userTOTPRepositoryImpl) Enable(ctx context.Context, userTOTP *entity.UserTOTP) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_totps set is_enabled = true, last_used_step = $2, confirmed_at = now(), updated_at = now()
+		where id = $1 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, userTOTP.ID, userTOTP.LastUsedStep); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *userTOTPRepositoryImpl) UpdateLastUsedStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_totps set last_used_step = $2, updated_at = now()
+		where id = $1 and last_used_step < $2 and deleted_at is null
+	`
+
+	result, err := db(ctx, query, id, step)
+	if err != nil {
+		return false, apperrorPkg.NewServerError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, apperrorPkg.NewServerError(err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *userTOTPRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_totps set updated_at = now(), deleted_at = now()
+		where user_id = $1 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, userID); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
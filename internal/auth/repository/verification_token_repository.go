@@ -15,6 +15,7 @@ import (
 
 type VerificationTokenRepository interface {
 	FindByVerificationToken(ctx context.Context, token, userID uuid.UUID) (*entity.VerificationToken, error)
+	FindHistoryByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.VerificationToken, error)
 	Save(ctx context.Context, verificationToken *entity.VerificationToken) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
@@ -92,3 +93,41 @@ func (r *verificationTokenRepositoryImpl) DeleteByUserID(ctx context.Context, us
 
 	return nil
 }
+
+// FindHistoryByUserID also returns used and expired tokens, newest first.
+func (r *verificationTokenRepositoryImpl) FindHistoryByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.VerificationToken, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, created_at, updated_at, deleted_at from token_verification_users where user_id = $1 order by created_at desc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.VerificationToken, 0)
+
+	for rows.Next() {
+		item := &entity.VerificationToken{
+			UserID: userID,
+		}
+
+		if err := rows.Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
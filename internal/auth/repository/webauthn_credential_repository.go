@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+
+	"github.com/google/uuid"
+)
+
+type WebAuthnCredentialRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebAuthnCredential, error)
+	FindByID(ctx context.Context, id, userID uuid.UUID) (*entity.WebAuthnCredential, error)
+	FindByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error)
+	Save(ctx context.Context, credential *entity.WebAuthnCredential) error
+	UpdateName(ctx context.Context, credential *entity.WebAuthnCredential) error
+	UpdateCredential(ctx context.Context, credential *entity.WebAuthnCredential) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type webAuthnCredentialRepositoryImpl struct {
+	db database.Executor
+}
+
+func NewWebAuthnCredentialRepository(db database.Executor) *webAuthnCredentialRepositoryImpl {
+	return &webAuthnCredentialRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *webAuthnCredentialRepositoryImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebAuthnCredential, error) {
+	db := r.db.QueryContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryContext
+	}
+
+	query := `
+		select id, user_id, credential_id, name, credential, last_used_at, created_at, updated_at
+		from user_webauthn_credentials
+		where user_id = $1 and deleted_at is null
+		order by created_at desc
+	`
+
+	rows, err := db(ctx, query, userID)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.WebAuthnCredential, 0)
+
+	for rows.Next() {
+		item := &entity.WebAuthnCredential{}
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.CredentialID,
+			&item.Name,
+			&item.Credential,
+			&item.LastUsedAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return result, nil
+}
+
+func (r *webAuthnCredentialRepositoryImpl) FindByID(ctx context.Context, id, userID uuid.UUID) (*entity.WebAuthnCredential, error) {
+	return r.findOne(ctx, "id = $1 and user_id = $2", id, userID)
+}
+
+func (r *webAuthnCredentialRepositoryImpl) FindByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error) {
+	return r.findOne(ctx, "credential_id = $1", credentialID)
+}
+
+func (r *webAuthnCredentialRepositoryImpl) findOne(ctx context.Context, where string, args ...any) (*entity.WebAuthnCredential, error) {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		select id, user_id, credential_id, name, credential, last_used_at, created_at, updated_at
+		from user_webauthn_credentials
+		where ` + where + ` and deleted_at is null
+	`
+
+	item := &entity.WebAuthnCredential{}
+
+	if err := db(ctx, query, args...).Scan(
+		&item.ID,
+		&item.UserID,
+		&item.CredentialID,
+		&item.Name,
+		&item.Credential,
+		&item.LastUsedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return item, nil
+}
+
+func (r *webAuthnCredentialRepositoryImpl) Save(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	db := r.db.QueryRowContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.QueryRowContext
+	}
+
+	query := `
+		insert into user_webauthn_credentials (user_id, credential_id, name, credential)
+		values ($1, $2, $3, $4)
+		returning id, created_at
+	`
+
+	if err := db(
+		ctx,
+		query,
+		credential.UserID,
+		credential.CredentialID,
+		credential.Name,
+		credential.Credential,
+	).Scan(&credential.ID, &credential.CreatedAt); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *webAuthnCredentialRepositoryImpl) UpdateName(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_webauthn_credentials set name = $3, updated_at = now()
+		where id = $1 and user_id = $2 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, credential.ID, credential.UserID, credential.Name); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *webAuthnCredentialRepositoryImpl) UpdateCredential(ctx context.Context, credential *entity.WebAuthnCredential) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_webauthn_credentials set credential = $2, last_used_at = now(), updated_at = now()
+		where id = $1 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, credential.ID, credential.Credential); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (r *webAuthnCredentialRepositoryImpl) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	db := r.db.ExecContext
+	if tx := transactor.ExtractTx(ctx); tx != nil {
+		db = tx.ExecContext
+	}
+
+	query := `
+		update user_webauthn_credentials set updated_at = now(), deleted_at = now()
+		where id = $1 and user_id = $2 and deleted_at is null
+	`
+
+	if _, err := db(ctx, query, id, userID); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
@@ -1,45 +1,140 @@
 package route
 
 import (
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/controller"
 	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	"github.com/faisalyudiansah/auth-service-template/pkg/middleware"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ratelimitutils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthControllerRoute(c *controller.AuthController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware) {
+func AuthControllerRoute(c *controller.AuthController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
 	g := r.Group("/auth")
 	{
-		g.POST("/login", c.Login)
-		g.POST("/register", c.Register)
+		g.POST("/login", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyLogin), c.Login)
+		g.POST("/login/2fa", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyLogin), c.LoginTwoFactor)
+		g.POST("/magic-link", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.RequestMagicLink)
+		g.POST("/magic-link/login", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyLogin), c.LoginMagicLink)
+		g.POST("/phone-otp", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.RequestPhoneOTP)
+		g.POST("/phone-otp/login", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyLogin), c.LoginPhoneOTP)
+		g.POST("/register", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.Register)
 		g.POST("/register/from-admin", authMiddleware.Authorization(), authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.RegisterFromAdmin)
-		g.POST("/refresh", authMiddleware.Authorization(), c.RefreshToken)
+		g.POST("/refresh", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyToken), c.RefreshToken)
 		g.POST("/logout", authMiddleware.Authorization(), c.Logout)
-		g.POST("/forgot-password", c.ForgotPassword)
-		g.POST("/reset-password", c.ResetPassword)
-		g.POST("/send-verification", c.SendVerification)
-		g.POST("/verify-account", c.VerifyAccount)
+		g.POST("/logout-all", authMiddleware.Authorization(), c.LogoutAll)
+		g.POST("/forgot-password", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.ForgotPassword)
+		g.POST("/reset-password", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.ResetPassword)
+		g.POST("/send-verification", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.SendVerification)
+		g.POST("/verify-account", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.VerifyAccount)
+		g.POST("/change-email", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.RequestEmailChange)
+		g.POST("/confirm-email-change", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.ConfirmEmailChange)
 		g.PATCH("/inactive-account", authMiddleware.Authorization(), c.InactiveAccount)
+		g.PATCH("/inactive-account/:user_id", authMiddleware.Authorization(), authMiddleware.OnlySelfOrAdmin("user_id"), c.InactiveAccount)
+		g.PATCH("/unlock-account/:user_id", authMiddleware.Authorization(), authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.UnlockAccount)
+		g.PATCH("/suspend-account/:user_id", authMiddleware.Authorization(), authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.SuspendAccount)
+		g.PATCH("/activate-account/:user_id", authMiddleware.Authorization(), authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.ActivateAccount)
+		g.POST("/reactivation", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.RequestReactivation)
+		g.POST("/reactivate", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.ConfirmReactivation)
 	}
 }
 
-func ProfileControlRoute(c *controller.ProfileController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware) {
-	g := r.Group("/user", authMiddleware.Authorization())
+func ProfileControlRoute(c *controller.ProfileController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/user", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyUser))
 	{
 		g.GET("", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.GetList)
 		g.GET("/me", c.GetMe)
+		g.POST("/me/password", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.ChangePassword)
+		g.POST("/me/phone/send-code", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.SendPhoneVerification)
+		g.POST("/me/phone/verify", c.VerifyPhoneNumber)
+		g.POST("/me/deletion", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.RequestAccountDeletion)
 		g.GET("/:user_id", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.GetUserByID)
 		g.PUT("/:user_id", authMiddleware.OnlySelfOrAdmin("user_id"), c.UpdateUser)
 		g.DELETE("/:user_id", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.DeleteUser)
 	}
 }
 
-func OauthControllerRoute(c *controller.OauthController, r *gin.Engine) {
+func SessionControllerRoute(c *controller.SessionController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/user", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyUser))
+	{
+		g.GET("/me/sessions", c.GetMySessions)
+		g.DELETE("/me/sessions/:session_id", c.RevokeMySession)
+		g.GET("/:user_id/sessions", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.GetUserSessions)
+		g.DELETE("/:user_id/sessions/:session_id", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.RevokeUserSession)
+	}
+}
+
+func TwoFactorControllerRoute(c *controller.TwoFactorController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/user", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyUser))
+	{
+		g.POST("/me/2fa/enroll", c.Enroll)
+		g.POST("/me/2fa/confirm", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.Confirm)
+		g.POST("/me/2fa/disable", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.Disable)
+		g.DELETE("/:user_id/2fa", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.Reset)
+	}
+}
+
+func OauthControllerRoute(c *controller.OauthController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware) {
 	g := r.Group("/oauth")
 	{
-		g.GET("/:provider/login", c.Login)
-		g.GET("/:provider/callback", c.Callback)
-		g.GET("/:provider/logout", c.Logout)
+		g.GET("/providers", c.GetProviders)
+		g.GET("/:provider/login", c.RequireProvider, c.Login)
+		g.GET("/:provider/callback", c.RequireProvider, authMiddleware.OptionalAuthorization(), c.Callback)
+		g.GET("/:provider/logout", c.RequireProvider, c.Logout)
+	}
+}
+
+func WellKnownControllerRoute(c *controller.WellKnownController, r *gin.Engine) {
+	g := r.Group("/.well-known")
+	{
+		g.GET("/jwks.json", c.JWKS)
+		g.GET("/openid-configuration", c.OpenIDConfiguration)
+	}
+}
+
+func OidcControllerRoute(c *controller.OidcController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/oauth2")
+	{
+		g.GET("/authorize", authMiddleware.OptionalAuthorization(), c.Authorize)
+		g.POST("/token", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyToken), c.Token)
+	}
+
+	r.GET("/userinfo", authMiddleware.ClientAuthorization(constantAuth.OIDC_SCOPE_OPENID), c.UserInfo)
+	r.POST("/userinfo", authMiddleware.ClientAuthorization(constantAuth.OIDC_SCOPE_OPENID), c.UserInfo)
+}
+
+func WebAuthnControllerRoute(c *controller.WebAuthnController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/user", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyUser))
+	{
+		g.POST("/me/passkeys/register/begin", c.BeginRegistration)
+		g.POST("/me/passkeys/register/finish", c.FinishRegistration)
+		g.GET("/me/passkeys", c.GetMyPasskeys)
+		g.PATCH("/me/passkeys/:passkey_id", c.RenameMyPasskey)
+		g.DELETE("/me/passkeys/:passkey_id", c.DeleteMyPasskey)
+	}
+
+	a := r.Group("/auth")
+	{
+		a.POST("/passkey/login/begin", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyToken), c.BeginLogin)
+		a.POST("/passkey/login/finish", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyLogin), c.FinishLogin)
+	}
+}
+
+func DataExportControllerRoute(c *controller.DataExportController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/user", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyUser))
+	{
+		g.POST("/me/export", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.RequestMyExport)
+		g.POST("/me/export/download", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.Download)
+		g.POST("/:user_id/export", authMiddleware.ProtectedRoles(custom_type.RoleAdmin), c.RequestUserExport)
+	}
+}
+
+func IdentityControllerRoute(c *controller.IdentityController, r *gin.Engine, authMiddleware *middleware.AuthMiddleware, rateLimitMiddleware *middleware.RateLimitMiddleware) {
+	g := r.Group("/user", authMiddleware.Authorization(), rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyUser))
+	{
+		g.GET("/me/identities", c.GetMyIdentities)
+		g.POST("/me/identities", rateLimitMiddleware.RateLimiter(ratelimitutils.PolicySensitive), c.LinkMyIdentity)
+		g.DELETE("/me/identities/:identity_id", c.UnlinkMyIdentity)
 	}
 }
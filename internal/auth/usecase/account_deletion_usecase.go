@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/tasks"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	constantPkg "github.com/faisalyudiansah/auth-service-template/pkg/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/cloudinaryutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+
+	"github.com/google/uuid"
+)
+
+type AccountDeletionUsecase interface {
+	Schedule(ctx context.Context, user *entity.User) error
+	PurgeDue(ctx context.Context) error
+}
+
+type accountDeletionUsecaseImpl struct {
+	userRepo             repository.UserRepository
+	userDetailRepo       repository.UserDetailRepository
+	accountPurgeRepo     repository.AccountPurgeRepository
+	accountStatusUsecase AccountStatusUsecase
+	redisUtil            redisutils.RedisUtil
+	cloudinaryUtil       cloudinaryutils.CloudinaryUtil
+	emailTask            tasks.EmailTask
+	transactor           transactor.Transactor
+	cfg                  *config.Config
+}
+
+func NewAccountDeletionUsecase(
+	userRepo repository.UserRepository,
+	userDetailRepo repository.UserDetailRepository,
+	accountPurgeRepo repository.AccountPurgeRepository,
+	accountStatusUsecase AccountStatusUsecase,
+	redisUtil redisutils.RedisUtil,
+	cloudinaryUtil cloudinaryutils.CloudinaryUtil,
+	emailTask tasks.EmailTask,
+	transactor transactor.Transactor,
+	cfg *config.Config,
+) *accountDeletionUsecaseImpl {
+	return &accountDeletionUsecaseImpl{
+		userRepo:             userRepo,
+		userDetailRepo:       userDetailRepo,
+		accountPurgeRepo:     accountPurgeRepo,
+		accountStatusUsecase: accountStatusUsecase,
+		redisUtil:            redisUtil,
+		cloudinaryUtil:       cloudinaryUtil,
+		emailTask:            emailTask,
+		transactor:           transactor,
+		cfg:                  cfg,
+	}
+}
+
+func (u *accountDeletionUsecaseImpl) gracePeriod() time.Duration {
+	return time.Duration(u.cfg.AccountDeletion.GracePeriodDays) * 24 * time.Hour
+}
+
+// Schedule signs the user out and mails a link that keeps the account, which
+// is a reactivation token living as long as the grace period. The deletion is
+// already committed when the link or its email fails, the user is told to
+// request a new link instead of getting a bare server error.
+func (u *accountDeletionUsecaseImpl) Schedule(ctx context.Context, user *entity.User) error {
+	reason := constantAuth.AccountDeletionRequestedReason
+
+	err := u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		return u.accountStatusUsecase.Transition(txCtx, user, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusPendingDeletion,
+			ActorID: user.ID,
+			Reason:  &reason,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	reactivation := &entity.Reactivation{
+		Token:     uuid.New(),
+		UserID:    user.ID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := u.redisUtil.SetJSON(ctx, utils.ReactivationTokenKey(reactivation.Token), reactivation, u.gracePeriod()); err != nil {
+		logger.FromContext(ctx).Errorf("error storing reactivation token of user %v scheduled for deletion: %v", user.ID, err)
+		return apperrorAuth.NewAccountDeletionEmailError(err)
+	}
+
+	err = u.emailTask.QueueAccountDeletionEmail(ctx, &payload.AccountDeletionEmailPayload{
+		Email:    user.Email,
+		Token:    reactivation.Token.String(),
+		DeleteAt: user.StatusChangedAt.Add(u.gracePeriod()).Format(constantPkg.DEFAULT_DATE_ONLY),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("error queueing account deletion email of user %v: %v", user.ID, err)
+		return apperrorAuth.NewAccountDeletionEmailError(err)
+	}
+
+	return nil
+}
+
+// PurgeDue purges a batch of accounts whose grace period has run out. One
+// failing account is logged and left for the next run. Every account is
+// claimed before it is purged, so instances running the job at the same time
+// never purge the same account twice.
+func (u *accountDeletionUsecaseImpl) PurgeDue(ctx context.Context) error {
+	requestedBefore := time.Now().Add(-u.gracePeriod())
+
+	userIDs, err := u.accountPurgeRepo.FindDue(ctx, requestedBefore, u.cfg.AccountDeletion.PurgeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := u.purge(ctx, userID, requestedBefore); err != nil {
+			logger.FromContext(ctx).Errorf("error purging account of user %v: %v", userID, err)
+		}
+	}
+
+	return nil
+}
+
+func (u *accountDeletionUsecaseImpl) purge(ctx context.Context, userID uuid.UUID, requestedBefore time.Time) error {
+	var (
+		user       *entity.User
+		userDetail *entity.UserDetail
+	)
+
+	reason := constantAuth.AccountPurgedReason
+
+	err := u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		claimed, err := u.accountPurgeRepo.ClaimDue(txCtx, userID, requestedBefore)
+		if err != nil {
+			return err
+		}
+
+		// another instance purges it, or it was reactivated in the meantime
+		if !claimed {
+			return nil
+		}
+
+		user, err = u.userRepo.Find(txCtx, "id", userID)
+		if err != nil {
+			return err
+		}
+
+		userDetail, err = u.userDetailRepo.Find(txCtx, "user_id", userID)
+		if err != nil && err != apperrorPkg.NewNoRowsError(err, userID).OriginalError() {
+			return err
+		}
+
+		if err := u.accountStatusUsecase.Transition(txCtx, user, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusDeleted,
+			ActorID: user.ID,
+			Reason:  &reason,
+		}); err != nil {
+			return err
+		}
+
+		return u.accountPurgeRepo.Purge(txCtx, user.ID, reason)
+	})
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	if err := u.redisUtil.Delete(ctx, utils.InactiveUserKey(user.ID), utils.UserEmailChangeKey(user.ID)); err != nil {
+		logger.FromContext(ctx).Errorf("error clearing cached state of purged user %v: %v", user.ID, err)
+	}
+
+	if userDetail != nil && userDetail.ImageURL != "" && userDetail.ImageURL != u.cfg.App.DefaultImageUserProfile {
+		if err := u.cloudinaryUtil.DeleteImage(ctx, userDetail.ImageURL); err != nil {
+			logger.FromContext(ctx).Errorf("error deleting profile image of purged user %v: %v", user.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+
+	"github.com/google/uuid"
+)
+
+// userStatusTransitions is the only place that decides which status an
+// account may move to, every change goes through Transition.
+var userStatusTransitions = map[custom_typeAuth.UserStatus][]custom_typeAuth.UserStatus{
+	custom_typeAuth.UserStatusPendingVerification: {
+		custom_typeAuth.UserStatusActive,
+		custom_typeAuth.UserStatusDeleted,
+	},
+	custom_typeAuth.UserStatusActive: {
+		custom_typeAuth.UserStatusSuspended,
+		custom_typeAuth.UserStatusDeactivated,
+		custom_typeAuth.UserStatusPendingDeletion,
+		custom_typeAuth.UserStatusDeleted,
+	},
+	custom_typeAuth.UserStatusSuspended: {
+		custom_typeAuth.UserStatusActive,
+		custom_typeAuth.UserStatusSuspended,
+		custom_typeAuth.UserStatusDeactivated,
+		custom_typeAuth.UserStatusPendingDeletion,
+		custom_typeAuth.UserStatusDeleted,
+	},
+	custom_typeAuth.UserStatusDeactivated: {
+		custom_typeAuth.UserStatusActive,
+		custom_typeAuth.UserStatusPendingDeletion,
+		custom_typeAuth.UserStatusDeleted,
+	},
+	custom_typeAuth.UserStatusPendingDeletion: {
+		custom_typeAuth.UserStatusActive,
+		custom_typeAuth.UserStatusDeleted,
+	},
+}
+
+type AccountStatusUsecase interface {
+	Transition(ctx context.Context, user *entity.User, change *entity.StatusChange) error
+}
+
+type accountStatusUsecaseImpl struct {
+	userRepo              repository.UserRepository
+	userStatusHistoryRepo repository.UserStatusHistoryRepository
+	redisUtil             redisutils.RedisUtil
+	sessionUsecase        SessionUsecase
+}
+
+func NewAccountStatusUsecase(
+	userRepo repository.UserRepository,
+	userStatusHistoryRepo repository.UserStatusHistoryRepository,
+	redisUtil redisutils.RedisUtil,
+	sessionUsecase SessionUsecase,
+) *accountStatusUsecaseImpl {
+	return &accountStatusUsecaseImpl{
+		userRepo:              userRepo,
+		userStatusHistoryRepo: userStatusHistoryRepo,
+		redisUtil:             redisUtil,
+		sessionUsecase:        sessionUsecase,
+	}
+}
+
+// Transition moves the account to change.To and records who did it and why.
+// Leaving active signs the user out everywhere and marks the account so the
+// auth middleware rejects any token that is still in flight.
+func (u *accountStatusUsecaseImpl) Transition(ctx context.Context, user *entity.User, change *entity.StatusChange) error {
+	from := user.Status
+	if !slices.Contains(userStatusTransitions[from], change.To) {
+		return apperrorAuth.NewInvalidStatusTransitionError(from, change.To)
+	}
+
+	currentTime := time.Now()
+
+	var suspendedUntil *time.Time
+	if change.To.IsUserStatusSuspended() {
+		if change.SuspendedUntil != nil && !change.SuspendedUntil.After(currentTime) {
+			return apperrorAuth.NewSuspendedUntilPastError()
+		}
+		suspendedUntil = change.SuspendedUntil
+	}
+
+	user.Status = change.To
+	user.StatusReason = change.Reason
+	user.SuspendedUntil = suspendedUntil
+	user.StatusChangedAt = &currentTime
+	user.StatusChangedBy = &change.ActorID
+	user.UpdatedBy = &change.ActorID
+
+	if err := u.userRepo.UpdateStatus(ctx, user); err != nil {
+		return err
+	}
+
+	if err := u.userStatusHistoryRepo.Save(ctx, &entity.UserStatusHistory{
+		UserID:         user.ID,
+		FromStatus:     from,
+		ToStatus:       change.To,
+		Reason:         change.Reason,
+		SuspendedUntil: suspendedUntil,
+		ActorID:        change.ActorID,
+	}); err != nil {
+		return err
+	}
+
+	var markerTTL time.Duration
+	if suspendedUntil != nil {
+		markerTTL = suspendedUntil.Sub(currentTime)
+	}
+
+	// the marker and the sessions live in Redis, they are only touched once
+	// the status change has committed so a rollback leaves them alone
+	userID, to := user.ID, change.To
+	return transactor.AfterCommit(ctx, func(ctx context.Context) error {
+		return u.applyStatus(ctx, userID, to, markerTTL)
+	})
+}
+
+func (u *accountStatusUsecaseImpl) applyStatus(ctx context.Context, userID uuid.UUID, to custom_typeAuth.UserStatus, markerTTL time.Duration) error {
+	switch to {
+	case custom_typeAuth.UserStatusActive:
+		if err := u.redisUtil.Delete(ctx, utils.InactiveUserKey(userID)); err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+		return nil
+	case custom_typeAuth.UserStatusDeleted:
+		return u.sessionUsecase.RevokeAll(ctx, userID)
+	}
+
+	if err := u.redisUtil.Set(ctx, utils.InactiveUserKey(userID), to.String(), markerTTL); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return u.sessionUsecase.RevokeAll(ctx, userID)
+}
+
+// checkUserStatus tells whether the account may sign in or keep a session.
+func checkUserStatus(user *entity.User) error {
+	switch user.EffectiveStatus(time.Now()) {
+	case custom_typeAuth.UserStatusActive:
+		return nil
+	case custom_typeAuth.UserStatusPendingVerification:
+		return apperrorAuth.NewUnverifiedError()
+	case custom_typeAuth.UserStatusSuspended:
+		return apperrorAuth.NewAccountSuspendedError(user.SuspendedUntil)
+	case custom_typeAuth.UserStatusPendingDeletion:
+		return apperrorAuth.NewAccountPendingDeletionError()
+	default:
+		return apperrorAuth.NewAccountInactiveError()
+	}
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+
+	"github.com/google/uuid"
+)
+
+var allUserStatuses = []custom_typeAuth.UserStatus{
+	custom_typeAuth.UserStatusPendingVerification,
+	custom_typeAuth.UserStatusActive,
+	custom_typeAuth.UserStatusSuspended,
+	custom_typeAuth.UserStatusDeactivated,
+	custom_typeAuth.UserStatusPendingDeletion,
+	custom_typeAuth.UserStatusDeleted,
+}
+
+func newTestAccountStatusUsecase(users ...*entity.User) (*accountStatusUsecaseImpl, *fakeUserRepository, *fakeRedisUtil, *fakeSessionUsecase) {
+	userRepo := newFakeUserRepository(users...)
+	redisUtil := newFakeRedisUtil()
+	sessionUsecase := &fakeSessionUsecase{}
+
+	return NewAccountStatusUsecase(userRepo, &fakeUserStatusHistoryRepository{}, redisUtil, sessionUsecase), userRepo, redisUtil, sessionUsecase
+}
+
+func TestAccountStatusUsecase_TransitionTable(t *testing.T) {
+	for _, from := range allUserStatuses {
+		for _, to := range allUserStatuses {
+			allowed := slices.Contains(userStatusTransitions[from], to)
+
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+				user := &entity.User{ID: uuid.New(), Status: from}
+				usecase, userRepo, redisUtil, sessionUsecase := newTestAccountStatusUsecase(user)
+
+				err := usecase.Transition(context.Background(), user, &entity.StatusChange{To: to, ActorID: uuid.New()})
+
+				if !allowed {
+					if err == nil {
+						t.Fatalf("expected %v -> %v to be rejected", from, to)
+					}
+					if userRepo.statusUpdates != 0 || len(sessionUsecase.revokedAll) != 0 {
+						t.Fatalf("rejected transition must not have side effects")
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("expected %v -> %v to be allowed, got %v", from, to, err)
+				}
+
+				if userRepo.users[user.ID].Status != to {
+					t.Fatalf("stored status = %v, want %v", userRepo.users[user.ID].Status, to)
+				}
+
+				marker, _ := redisUtil.Get(context.Background(), utils.InactiveUserKey(user.ID))
+				wantMarker := to != custom_typeAuth.UserStatusActive && to != custom_typeAuth.UserStatusDeleted
+				if (marker != "") != wantMarker {
+					t.Fatalf("inactive marker = %q, want set %v", marker, wantMarker)
+				}
+
+				wantRevoked := to != custom_typeAuth.UserStatusActive
+				if (len(sessionUsecase.revokedAll) == 1) != wantRevoked {
+					t.Fatalf("sessions revoked %d times, want revoked %v", len(sessionUsecase.revokedAll), wantRevoked)
+				}
+			})
+		}
+	}
+}
+
+func TestAccountStatusUsecase_TransitionVerifiesPendingAccount(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), Status: custom_typeAuth.UserStatusPendingVerification}
+	usecase, userRepo, _, sessionUsecase := newTestAccountStatusUsecase(user)
+
+	if user.IsVerified() {
+		t.Fatalf("pending account must not be verified")
+	}
+
+	if err := usecase.Transition(context.Background(), user, &entity.StatusChange{
+		To:      custom_typeAuth.UserStatusActive,
+		ActorID: user.ID,
+	}); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+
+	if !user.IsVerified() || !userRepo.users[user.ID].IsVerified() {
+		t.Fatalf("account must be verified once active")
+	}
+
+	if checkUserStatus(user) != nil {
+		t.Fatalf("verified account must be allowed to sign in")
+	}
+
+	if len(sessionUsecase.revokedAll) != 0 {
+		t.Fatalf("verifying an account must not revoke its sessions")
+	}
+}
+
+func TestAccountStatusUsecase_TransitionSuspension(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		suspendedUntil *time.Time
+		wantErr        bool
+		wantMarkerTTL  bool
+	}{
+		{name: "indefinite", suspendedUntil: nil},
+		{name: "until a future time", suspendedUntil: &future, wantMarkerTTL: true},
+		{name: "until a past time", suspendedUntil: &past, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entity.User{ID: uuid.New(), Status: custom_typeAuth.UserStatusActive}
+			usecase, _, redisUtil, _ := newTestAccountStatusUsecase(user)
+
+			err := usecase.Transition(context.Background(), user, &entity.StatusChange{
+				To:             custom_typeAuth.UserStatusSuspended,
+				ActorID:        uuid.New(),
+				SuspendedUntil: tt.suspendedUntil,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			ttl, _ := redisUtil.TTL(context.Background(), utils.InactiveUserKey(user.ID))
+			if (ttl > 0) != tt.wantMarkerTTL {
+				t.Fatalf("marker ttl = %v, want expiring %v", ttl, tt.wantMarkerTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+
+	"github.com/google/uuid"
+)
+
+type AuditEventUsecase interface {
+	Record(ctx context.Context, userID uuid.UUID, event string, detail *string) error
+	GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserAuditEvent, error)
+}
+
+type auditEventUsecaseImpl struct {
+	userAuditEventRepo repository.UserAuditEventRepository
+}
+
+func NewAuditEventUsecase(
+	userAuditEventRepo repository.UserAuditEventRepository,
+) *auditEventUsecaseImpl {
+	return &auditEventUsecaseImpl{
+		userAuditEventRepo: userAuditEventRepo,
+	}
+}
+
+// Record takes the actor, client ip and user agent from the request. An event
+// without a signed in user, such as a sign-in or a confirmed email link, is
+// caused by the user themselves.
+func (u *auditEventUsecaseImpl) Record(ctx context.Context, userID uuid.UUID, event string, detail *string) error {
+	actorID := utils.GetValueUserIDFromContext(ctx)
+	if actorID == uuid.Nil {
+		actorID = userID
+	}
+
+	return u.userAuditEventRepo.Save(ctx, &entity.UserAuditEvent{
+		UserID:    userID,
+		Event:     event,
+		Detail:    detail,
+		ActorID:   actorID,
+		ClientIP:  utils.GetClientIPFromContext(ctx),
+		UserAgent: utils.GetUserAgentFromContext(ctx),
+	})
+}
+
+func (u *auditEventUsecaseImpl) GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserAuditEvent, error) {
+	return u.userAuditEventRepo.FindByUserID(ctx, userID)
+}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
@@ -16,45 +18,81 @@ import (
 	"github.com/faisalyudiansah/auth-service-template/internal/queue/tasks"
 	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
 	constantPkg "github.com/faisalyudiansah/auth-service-template/pkg/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
 	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/breachutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 type AuthUsecase interface {
-	Login(ctx context.Context, req *dto_request.Login) (*entity.User, *entity.Session, error)
+	Login(ctx context.Context, req *dto_request.Login) (*entity.User, *entity.Session, *entity.LoginChallenge, error)
+	LoginTwoFactor(ctx context.Context, req *dto_request.LoginTwoFactor) (*entity.User, *entity.Session, error)
+	RequestMagicLink(ctx context.Context, req *dto_request.RequestMagicLink) error
+	LoginMagicLink(ctx context.Context, req *dto_request.MagicLinkLogin) (*entity.User, *entity.Session, *entity.LoginChallenge, error)
+	RequestPhoneOTP(ctx context.Context, req *dto_request.RequestPhoneOTP) error
+	LoginPhoneOTP(ctx context.Context, req *dto_request.PhoneOTPLogin) (*entity.User, *entity.Session, *entity.LoginChallenge, error)
 	RefreshToken(ctx context.Context, sessionID uuid.UUID) (*entity.Session, error)
+	RefreshTokenByToken(ctx context.Context, req *dto_request.RefreshToken) (*entity.Session, error)
 	Logout(ctx context.Context, sessionID uuid.UUID) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
 	Register(ctx context.Context, req *dto_request.Register) (*entity.User, error)
 	SendVerification(ctx context.Context, req *dto_request.SendVerification) error
 	VerifyAccount(ctx context.Context, req *dto_request.VerifyAccount) error
 	ForgotPassword(ctx context.Context, req *dto_request.ForgotPassword) error
 	ResetPassword(ctx context.Context, req *dto_request.ResetPassword) error
+	RequestEmailChange(ctx context.Context, req *dto_request.ChangeEmail) error
+	ConfirmEmailChange(ctx context.Context, req *dto_request.ConfirmEmailChange) error
 	InactiveAccount(ctx context.Context, req *dto_request.InactiveAccount) error
+	SuspendAccount(ctx context.Context, req *dto_request.SuspendAccount) error
+	ActivateAccount(ctx context.Context, req *dto_request.ActivateAccount) error
+	RequestReactivation(ctx context.Context, req *dto_request.RequestReactivation) error
+	ConfirmReactivation(ctx context.Context, req *dto_request.ConfirmReactivation) error
+	UnlockAccount(ctx context.Context, userID uuid.UUID) error
 }
 
 type authUsecaseImpl struct {
-	userRepo              repository.UserRepository
-	userDetailRepo        repository.UserDetailRepository
-	redisUtil             redisutils.RedisUtil
-	jwtUtil               jwtutils.JwtUtilInterface
-	passwordEncryptor     encryptutils.PasswordEncryptor
-	base64Encryptor       encryptutils.Base64Encryptor
-	emailTask             tasks.EmailTask
-	resetTokenRepo        repository.ResetTokenRepository
-	verificationTokenRepo repository.VerificationTokenRepository
-	transactor            transactor.Transactor
+	userRepo               repository.UserRepository
+	userDetailRepo         repository.UserDetailRepository
+	userIdentityRepo       repository.UserIdentityRepository
+	redisUtil              redisutils.RedisUtil
+	jwtUtil                jwtutils.JwtUtilInterface
+	sessionUsecase         SessionUsecase
+	twoFactorUsecase       TwoFactorUsecase
+	loginAttemptUsecase    LoginAttemptUsecase
+	passwordHistoryUsecase PasswordHistoryUsecase
+	phoneOTPUsecase        PhoneOTPUsecase
+	accountStatusUsecase   AccountStatusUsecase
+	auditEventUsecase      AuditEventUsecase
+	passwordEncryptor      encryptutils.PasswordEncryptor
+	breachChecker          breachutils.BreachChecker
+	base64Encryptor        encryptutils.Base64Encryptor
+	emailTask              tasks.EmailTask
+	resetTokenRepo         repository.ResetTokenRepository
+	verificationTokenRepo  repository.VerificationTokenRepository
+	transactor             transactor.Transactor
 }
 
 func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	userDetailRepo repository.UserDetailRepository,
+	userIdentityRepo repository.UserIdentityRepository,
 	redisUtil redisutils.RedisUtil,
 	jwtUtil jwtutils.JwtUtilInterface,
+	sessionUsecase SessionUsecase,
+	twoFactorUsecase TwoFactorUsecase,
+	loginAttemptUsecase LoginAttemptUsecase,
+	passwordHistoryUsecase PasswordHistoryUsecase,
+	phoneOTPUsecase PhoneOTPUsecase,
+	accountStatusUsecase AccountStatusUsecase,
+	auditEventUsecase AuditEventUsecase,
 	passwordEncryptor encryptutils.PasswordEncryptor,
+	breachChecker breachutils.BreachChecker,
 	base64Encryptor encryptutils.Base64Encryptor,
 	emailTask tasks.EmailTask,
 	resetTokenRepo repository.ResetTokenRepository,
@@ -62,144 +100,335 @@ func NewAuthUsecase(
 	transactor transactor.Transactor,
 ) *authUsecaseImpl {
 	return &authUsecaseImpl{
-		userRepo:              userRepo,
-		userDetailRepo:        userDetailRepo,
-		redisUtil:             redisUtil,
-		jwtUtil:               jwtUtil,
-		passwordEncryptor:     passwordEncryptor,
-		base64Encryptor:       base64Encryptor,
-		emailTask:             emailTask,
-		resetTokenRepo:        resetTokenRepo,
-		verificationTokenRepo: verificationTokenRepo,
-		transactor:            transactor,
+		userRepo:               userRepo,
+		userDetailRepo:         userDetailRepo,
+		userIdentityRepo:       userIdentityRepo,
+		redisUtil:              redisUtil,
+		jwtUtil:                jwtUtil,
+		sessionUsecase:         sessionUsecase,
+		twoFactorUsecase:       twoFactorUsecase,
+		loginAttemptUsecase:    loginAttemptUsecase,
+		passwordHistoryUsecase: passwordHistoryUsecase,
+		phoneOTPUsecase:        phoneOTPUsecase,
+		accountStatusUsecase:   accountStatusUsecase,
+		auditEventUsecase:      auditEventUsecase,
+		passwordEncryptor:      passwordEncryptor,
+		breachChecker:          breachChecker,
+		base64Encryptor:        base64Encryptor,
+		emailTask:              emailTask,
+		resetTokenRepo:         resetTokenRepo,
+		verificationTokenRepo:  verificationTokenRepo,
+		transactor:             transactor,
 	}
 }
 
-func (u *authUsecaseImpl) Login(ctx context.Context, req *dto_request.Login) (*entity.User, *entity.Session, error) {
-	currentTime := time.Now()
+func (u *authUsecaseImpl) Login(ctx context.Context, req *dto_request.Login) (*entity.User, *entity.Session, *entity.LoginChallenge, error) {
+	if err := u.loginAttemptUsecase.Check(ctx, req.Email, req.ClientIP); err != nil {
+		return nil, nil, nil, err
+	}
 
 	recordUserDB, err := u.userRepo.Find(ctx, "email", req.Email)
 	if err != nil {
 		if err != apperrorPkg.NewNoRowsError(err, req.Email) {
-			return nil, nil, apperrorAuth.NewEmailNotExistsError()
+			return nil, nil, nil, u.loginFailed(ctx, req, apperrorAuth.NewEmailNotExistsError())
 		}
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if recordUserDB == nil || (recordUserDB.IsOauth && recordUserDB.HashPassword == "") {
-		return nil, nil, apperrorAuth.NewEmailNotExistsError()
+		return nil, nil, nil, u.loginFailed(ctx, req, apperrorAuth.NewEmailNotExistsError())
 	}
 
 	isValid := u.passwordEncryptor.Check(req.Password, recordUserDB.HashPassword)
 	if !isValid {
-		return nil, nil, apperrorAuth.NewInvalidLoginCredentials(nil)
+		return nil, nil, nil, u.loginFailed(ctx, req, apperrorAuth.NewInvalidLoginCredentials(nil))
+	}
+
+	if err := u.loginAttemptUsecase.RecordSuccess(ctx, req.Email, req.ClientIP); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if u.passwordEncryptor.NeedsRehash(recordUserDB.HashPassword) {
+		u.rehashPassword(ctx, recordUserDB, req.Password)
+	}
+
+	return u.startSession(ctx, recordUserDB, entity.SessionOptions{
+		RememberMe:  req.RememberMe,
+		LoginMethod: constantAuth.LoginMethodPassword,
+	})
+}
+
+// startSession finishes a first-factor login: users with two-factor
+// authentication get a challenge, everyone else a session.
+func (u *authUsecaseImpl) startSession(ctx context.Context, user *entity.User, sessionOptions entity.SessionOptions) (*entity.User, *entity.Session, *entity.LoginChallenge, error) {
+	if err := checkUserStatus(user); err != nil {
+		return nil, nil, nil, err
 	}
 
-	if !recordUserDB.IsVerified {
-		return nil, nil, apperrorAuth.NewUnverifiedError()
+	isTwoFactorEnabled, err := u.twoFactorUsecase.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	jti := uuid.NewString()
-	accessToken, err := u.jwtUtil.Sign(recordUserDB.ID, recordUserDB.Role, jti, currentTime)
+	if isTwoFactorEnabled {
+		challenge, err := u.twoFactorUsecase.CreateChallenge(ctx, user.ID, sessionOptions)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return user, nil, challenge, nil
+	}
+
+	session, err := u.sessionUsecase.Create(ctx, user, sessionOptions)
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		return nil, nil, nil, err
 	}
 
-	refreshToken, err := u.jwtUtil.SignRefresh(currentTime)
+	return user, session, nil, nil
+}
+
+// rehashPassword upgrades a hash made with an older algorithm or weaker
+// parameters while the plain password is at hand. A failure only costs the
+// upgrade, so it is logged instead of failing the login.
+func (u *authUsecaseImpl) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	hashPassword, err := u.passwordEncryptor.Hash(password)
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		logger.FromContext(ctx).Errorf("error rehashing password of user %v: %v", user.ID, err)
+		return
 	}
 
-	sessionID := uuid.New()
+	user.HashPassword = hashPassword
+	user.UpdatedBy = &user.ID
+	if err := u.userRepo.UpdatePassword(ctx, user); err != nil {
+		logger.FromContext(ctx).Errorf("error saving rehashed password of user %v: %v", user.ID, err)
+	}
+}
+
+func (u *authUsecaseImpl) loginFailed(ctx context.Context, req *dto_request.Login, cause error) error {
+	if err := u.loginAttemptUsecase.RecordFailure(ctx, req.Email, req.ClientIP); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+func (u *authUsecaseImpl) LoginTwoFactor(ctx context.Context, req *dto_request.LoginTwoFactor) (*entity.User, *entity.Session, error) {
+	challenge, err := u.twoFactorUsecase.VerifyChallenge(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	payloadSession := &entity.Session{
-		UserID:       recordUserDB.ID,
-		Role:         recordUserDB.Role,
-		JTI:          jti,
-		SessionID:    sessionID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		LoginAt:      uint64(currentTime.UnixMilli()),
+	recordUserDB, err := u.userRepo.Find(ctx, "id", challenge.UserID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	err = u.redisUtil.Set(ctx, utils.SessionKey(sessionID), payloadSession, 24*time.Hour)
+	sessionOptions := challenge.SessionOptions
+	sessionOptions.LoginMethod += constantAuth.LoginMethodTOTPSuffix
+
+	session, err := u.sessionUsecase.Create(ctx, recordUserDB, sessionOptions)
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		return nil, nil, err
 	}
 
-	return recordUserDB, payloadSession, nil
+	return recordUserDB, session, nil
 }
 
-func (u *authUsecaseImpl) RefreshToken(ctx context.Context, sessionID uuid.UUID) (*entity.Session, error) {
-	currentTime := time.Now()
+// RequestMagicLink answers the same way whether or not the email belongs to an
+// account that can sign in, so it cannot be used to probe for accounts.
+func (u *authUsecaseImpl) RequestMagicLink(ctx context.Context, req *dto_request.RequestMagicLink) error {
+	email := normalizeLoginEmail(req.Email)
+
+	cachedMagicLink := new(entity.MagicLink)
+	if err := u.redisUtil.GetWithScanJSON(ctx, utils.MagicLinkCacheKey(email), cachedMagicLink); err == nil {
+		if time.Since(cachedMagicLink.CreatedAt) < constantAuth.MagicLinkTokenCooldownDuration {
+			return apperrorAuth.NewTokenAlreadyExistsError()
+		}
+	}
 
-	getSession := &entity.Session{}
-	sessionKey := utils.SessionKey(sessionID)
+	magicLink := &entity.MagicLink{
+		Token:      uuid.New(),
+		RememberMe: req.RememberMe,
+		CreatedAt:  time.Now(),
+	}
 
-	err := u.redisUtil.GetWithScanJSON(ctx, sessionKey, getSession)
-	if err != nil || getSession.UserID == uuid.Nil {
-		return nil, apperrorPkg.NewForbiddenAccessError()
+	if err := u.redisUtil.SetJSON(ctx, utils.MagicLinkCacheKey(email), magicLink, constantAuth.MagicLinkTokenCooldownDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
 	}
 
-	if getSession.RefreshToken == "" {
-		return nil, apperrorPkg.NewForbiddenAccessError()
+	userDb, err := u.userRepo.Find(ctx, "email", req.Email)
+	if err != nil && err != apperrorPkg.NewNoRowsError(err, req.Email).OriginalError() {
+		return err
+	}
+	if userDb == nil || checkUserStatus(userDb) != nil {
+		// enqueued without a token, which the worker drops, so the response
+		// time does not tell which addresses have an account
+		return u.emailTask.QueueMagicLinkEmail(ctx, &payload.MagicLinkEmailPayload{
+			Email: email,
+		})
+	}
+
+	// a new link replaces the previous one
+	previousToken, err := u.redisUtil.GetDel(ctx, utils.MagicLinkUserKey(userDb.ID))
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if parsePreviousToken, err := uuid.Parse(previousToken); err == nil {
+		if err := u.redisUtil.Delete(ctx, utils.MagicLinkTokenKey(parsePreviousToken)); err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+	}
+
+	magicLink.UserID = userDb.ID
+	if err := u.redisUtil.SetJSON(ctx, utils.MagicLinkTokenKey(magicLink.Token), magicLink, constantAuth.MagicLinkTokenExpireDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
 	}
 
-	_, err = u.jwtUtil.Parse(getSession.RefreshToken)
+	if err := u.redisUtil.Set(ctx, utils.MagicLinkUserKey(userDb.ID), magicLink.Token.String(), constantAuth.MagicLinkTokenExpireDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return u.emailTask.QueueMagicLinkEmail(ctx, &payload.MagicLinkEmailPayload{
+		Email: userDb.Email,
+		Token: magicLink.Token.String(),
+	})
+}
+
+func (u *authUsecaseImpl) LoginMagicLink(ctx context.Context, req *dto_request.MagicLinkLogin) (*entity.User, *entity.Session, *entity.LoginChallenge, error) {
+	token, err := u.base64Encryptor.DecodeURL(req.Token)
 	if err != nil {
-		_ = u.redisUtil.Delete(ctx, sessionKey)
-		return nil, apperrorPkg.NewSessionExpiredError()
+		return nil, nil, nil, apperrorAuth.NewInvalidMagicLinkError()
 	}
 
-	user, err := u.userRepo.Find(ctx, "id", getSession.UserID)
+	parseToken, err := uuid.Parse(token)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, apperrorAuth.NewInvalidMagicLinkError()
 	}
 
-	if user == nil {
-		return nil, apperrorPkg.NewForbiddenAccessError()
+	// GetDel makes the link single-use even when it is opened twice at once
+	val, err := u.redisUtil.GetDel(ctx, utils.MagicLinkTokenKey(parseToken))
+	if err != nil {
+		return nil, nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	if val == "" {
+		return nil, nil, nil, apperrorAuth.NewInvalidMagicLinkError()
+	}
+
+	magicLink := new(entity.MagicLink)
+	if err := json.Unmarshal([]byte(val), magicLink); err != nil {
+		return nil, nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	// only the latest link of the user signs in, even if an older one
+	// survived a concurrent request
+	currentToken, err := u.redisUtil.Get(ctx, utils.MagicLinkUserKey(magicLink.UserID))
+	if err != nil {
+		return nil, nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	if currentToken != magicLink.Token.String() {
+		return nil, nil, nil, apperrorAuth.NewInvalidMagicLinkError()
+	}
+
+	if err := u.redisUtil.Delete(ctx, utils.MagicLinkUserKey(magicLink.UserID)); err != nil {
+		return nil, nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	recordUserDB, err := u.userRepo.Find(ctx, "id", magicLink.UserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := u.loginAttemptUsecase.RecordSuccess(ctx, recordUserDB.Email, req.ClientIP); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return u.startSession(ctx, recordUserDB, entity.SessionOptions{
+		RememberMe:  magicLink.RememberMe,
+		LoginMethod: constantAuth.LoginMethodMagicLink,
+	})
+}
+
+// RequestPhoneOTP answers the same way for numbers that do not belong to a
+// verified phone, the code is then simply never sent.
+func (u *authUsecaseImpl) RequestPhoneOTP(ctx context.Context, req *dto_request.RequestPhoneOTP) error {
+	userDetailDB, err := u.userDetailRepo.Find(ctx, "phone_number", req.PhoneNumber)
+	if err != nil && err != apperrorPkg.NewNoRowsError(err, req.PhoneNumber).OriginalError() {
+		return err
+	}
+
+	otp := &entity.PhoneOTP{
+		PhoneNumber: req.PhoneNumber,
+		SessionOptions: entity.SessionOptions{
+			RememberMe:  req.RememberMe,
+			LoginMethod: constantAuth.LoginMethodSMSOTP,
+		},
+	}
+
+	if userDetailDB != nil && userDetailDB.PhoneVerifiedAt != nil {
+		otp.UserID = userDetailDB.UserID
 	}
 
-	newJTI := uuid.NewString()
+	return u.phoneOTPUsecase.Issue(ctx, constantAuth.PhoneOTPPurposeLogin, req.PhoneNumber, otp)
+}
 
-	newAccessToken, err := u.jwtUtil.Sign(user.ID, user.Role, newJTI, currentTime)
+func (u *authUsecaseImpl) LoginPhoneOTP(ctx context.Context, req *dto_request.PhoneOTPLogin) (*entity.User, *entity.Session, *entity.LoginChallenge, error) {
+	otp, err := u.phoneOTPUsecase.Verify(ctx, constantAuth.PhoneOTPPurposeLogin, req.PhoneNumber, req.Code)
 	if err != nil {
-		return nil, apperrorPkg.NewServerError(err)
+		return nil, nil, nil, err
 	}
 
-	newRefreshToken, err := u.jwtUtil.SignRefresh(currentTime)
+	userDetailDB, err := u.userDetailRepo.Find(ctx, "user_id", otp.UserID)
 	if err != nil {
-		return nil, apperrorPkg.NewServerError(err)
+		return nil, nil, nil, err
 	}
 
-	updatedSession := &entity.Session{
-		UserID:       user.ID,
-		Role:         user.Role,
-		JTI:          newJTI,
-		SessionID:    sessionID,
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-		LoginAt:      getSession.LoginAt,
+	if userDetailDB.PhoneNumber == nil || *userDetailDB.PhoneNumber != otp.PhoneNumber || userDetailDB.PhoneVerifiedAt == nil {
+		return nil, nil, nil, apperrorAuth.NewInvalidPhoneOTPError()
 	}
 
-	err = u.redisUtil.Set(ctx, sessionKey, updatedSession, 24*time.Hour)
+	recordUserDB, err := u.userRepo.Find(ctx, "id", otp.UserID)
 	if err != nil {
-		return nil, apperrorPkg.NewServerError(err)
+		return nil, nil, nil, err
 	}
 
-	return updatedSession, nil
+	return u.startSession(ctx, recordUserDB, otp.SessionOptions)
 }
 
-func (u *authUsecaseImpl) Logout(ctx context.Context, sessionID uuid.UUID) error {
-	err := u.redisUtil.Delete(ctx, utils.SessionKey(sessionID))
+func (u *authUsecaseImpl) RefreshToken(ctx context.Context, sessionID uuid.UUID) (*entity.Session, error) {
+	return u.sessionUsecase.Refresh(ctx, sessionID, "", "")
+}
+
+func (u *authUsecaseImpl) RefreshTokenByToken(ctx context.Context, req *dto_request.RefreshToken) (*entity.Session, error) {
+	claims, err := u.jwtUtil.Parse(req.RefreshToken, jwtutils.TokenTypeRefresh)
 	if err != nil {
-		return apperrorPkg.NewServerError(err)
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, apperrorPkg.NewSessionExpiredError()
+		}
+		return nil, apperrorPkg.NewForbiddenAccessError()
 	}
 
-	return nil
+	if claims.SessionID == uuid.Nil {
+		return nil, apperrorPkg.NewForbiddenAccessError()
+	}
+
+	return u.sessionUsecase.Refresh(ctx, claims.SessionID, req.RefreshToken, "")
+}
+
+func (u *authUsecaseImpl) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	return u.sessionUsecase.Revoke(ctx, uuid.Nil, sessionID)
+}
+
+func (u *authUsecaseImpl) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return u.sessionUsecase.RevokeAll(ctx, userID)
 }
 
 func (u *authUsecaseImpl) Register(ctx context.Context, req *dto_request.Register) (*entity.User, error) {
+	if u.breachChecker.IsBreached(req.Password) {
+		return nil, apperrorAuth.NewBreachedPasswordError()
+	}
+
 	var entityUser *entity.User
 	err := u.transactor.Atomic(ctx, func(txCtx context.Context) error {
 		checkEmail, err := u.userRepo.Find(txCtx, "email", req.Email)
@@ -223,7 +452,7 @@ func (u *authUsecaseImpl) Register(ctx context.Context, req *dto_request.Registe
 
 		if req.CreatedBy != nil { // admin create user
 			user.Role = *req.Role
-			user.IsVerified = true
+			user.Status = custom_typeAuth.UserStatusActive
 			user.CreatedBy = *req.CreatedBy
 		}
 
@@ -239,6 +468,10 @@ func (u *authUsecaseImpl) Register(ctx context.Context, req *dto_request.Registe
 			return err
 		}
 
+		if err := u.passwordHistoryUsecase.Record(txCtx, resUser); err != nil {
+			return err
+		}
+
 		resUserDetail, err := u.userDetailRepo.Save(txCtx, userDetail)
 		if err != nil {
 			return err
@@ -271,7 +504,7 @@ func (u *authUsecaseImpl) SendVerification(ctx context.Context, req *dto_request
 		if userDb == nil || userDb.IsOauth {
 			return apperrorAuth.NewEmailNotExistsError()
 		}
-		if userDb.IsVerified {
+		if userDb.IsVerified() {
 			return apperrorAuth.NewVerifiedError()
 		}
 
@@ -339,7 +572,7 @@ func (u *authUsecaseImpl) VerifyAccount(ctx context.Context, req *dto_request.Ve
 		if userDb == nil || userDb.IsOauth {
 			return apperrorAuth.NewAccoundIsNotValidError()
 		}
-		if userDb.IsVerified {
+		if userDb.IsVerified() {
 			return apperrorAuth.NewVerifiedError()
 		}
 
@@ -362,9 +595,10 @@ func (u *authUsecaseImpl) VerifyAccount(ctx context.Context, req *dto_request.Ve
 			return apperrorAuth.NewExpiredTokenError()
 		}
 
-		userDb.IsVerified = true
-		userDb.UpdatedBy = &userDb.ID
-		if err := u.userRepo.Update(txCtx, userDb); err != nil {
+		if err := u.accountStatusUsecase.Transition(txCtx, userDb, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusActive,
+			ActorID: userDb.ID,
+		}); err != nil {
 			return err
 		}
 		if err := u.verificationTokenRepo.DeleteByUserID(txCtx, userDb.ID); err != nil {
@@ -391,7 +625,7 @@ func (u *authUsecaseImpl) ForgotPassword(ctx context.Context, req *dto_request.F
 			return err
 		}
 
-		if userDb == nil || userDb.IsOauth {
+		if userDb == nil || (userDb.IsOauth && userDb.HashPassword == "") {
 			return apperrorAuth.NewEmailNotExistsError()
 		}
 
@@ -440,12 +674,16 @@ func (u *authUsecaseImpl) ResetPassword(ctx context.Context, req *dto_request.Re
 		return apperrorAuth.NewInvalidTokenCredentials()
 	}
 
+	if u.breachChecker.IsBreached(req.Password) {
+		return apperrorAuth.NewBreachedPasswordError()
+	}
+
 	err = u.transactor.Atomic(ctx, func(txCtx context.Context) error {
 		userDb, err := u.userRepo.Find(txCtx, "email", email)
 		if err != nil {
 			return err
 		}
-		if userDb == nil || userDb.IsOauth {
+		if userDb == nil || (userDb.IsOauth && userDb.HashPassword == "") {
 			return apperrorAuth.NewEmailNotExistsError()
 		}
 
@@ -460,6 +698,10 @@ func (u *authUsecaseImpl) ResetPassword(ctx context.Context, req *dto_request.Re
 			return apperrorAuth.NewExpiredTokenError()
 		}
 
+		if err := u.passwordHistoryUsecase.CheckReuse(txCtx, userDb, req.Password); err != nil {
+			return err
+		}
+
 		hashPassword, err := u.passwordEncryptor.Hash(req.Password)
 		if err != nil {
 			return apperrorPkg.NewServerError(err)
@@ -469,16 +711,183 @@ func (u *authUsecaseImpl) ResetPassword(ctx context.Context, req *dto_request.Re
 		if err := u.userRepo.UpdatePassword(txCtx, userDb); err != nil {
 			return err
 		}
+		if err := u.passwordHistoryUsecase.Record(txCtx, userDb); err != nil {
+			return err
+		}
 		if err := u.resetTokenRepo.DeleteByUserID(txCtx, userDb.ID); err != nil {
 			return err
 		}
 
-		return nil
+		return transactor.AfterCommit(txCtx, func(ctx context.Context) error {
+			return u.sessionUsecase.RevokeAll(ctx, userDb.ID)
+		})
 	})
 
 	return err
 }
 
+func (u *authUsecaseImpl) RequestEmailChange(ctx context.Context, req *dto_request.ChangeEmail) error {
+	cachedEmailChange := new(entity.EmailChange)
+	if err := u.redisUtil.GetWithScanJSON(ctx, utils.UserEmailChangeKey(req.UserID), cachedEmailChange); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	hasPendingEmailChange := cachedEmailChange.Token != uuid.Nil
+	if hasPendingEmailChange && time.Since(cachedEmailChange.CreatedAt) < constantAuth.EmailChangeTokenCooldownDuration {
+		return apperrorAuth.NewTokenAlreadyExistsError()
+	}
+
+	userDb, err := u.userRepo.Find(ctx, "id", req.UserID)
+	if err != nil {
+		return err
+	}
+
+	// an oauth account whose identity is not claimed yet is still found by
+	// the email the provider hands over, changing it could cut off its only
+	// login
+	unclaimed, err := u.userIdentityRepo.FindUnclaimedByUserID(ctx, userDb.ID)
+	if err != nil {
+		return err
+	}
+	if unclaimed != nil {
+		return apperrorAuth.NewOauthEmailChangeError()
+	}
+
+	if !u.passwordEncryptor.Check(req.Password, userDb.HashPassword) {
+		return apperrorAuth.NewInvalidLoginCredentials(nil)
+	}
+
+	if strings.EqualFold(userDb.Email, req.NewEmail) {
+		return apperrorAuth.NewSameEmailError()
+	}
+
+	checkEmail, err := u.userRepo.Find(ctx, "email", req.NewEmail)
+	if err != nil && err != apperrorPkg.NewNoRowsError(err, req.NewEmail).OriginalError() {
+		return err
+	}
+	if checkEmail != nil {
+		return apperrorAuth.NewInvalidEmailAlreadyExists(nil)
+	}
+
+	emailChange := &entity.EmailChange{
+		Token:     uuid.New(),
+		UserID:    userDb.ID,
+		SessionID: req.SessionID,
+		NewEmail:  req.NewEmail,
+		CreatedAt: time.Now(),
+	}
+
+	// only the latest link stays valid
+	if hasPendingEmailChange {
+		if err := u.redisUtil.Delete(ctx, utils.EmailChangeKey(cachedEmailChange.Token)); err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+	}
+
+	if err := u.redisUtil.SetJSON(ctx, utils.EmailChangeKey(emailChange.Token), emailChange, constantAuth.EmailChangeTokenExpireDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if err := u.redisUtil.SetJSON(ctx, utils.UserEmailChangeKey(userDb.ID), emailChange, constantAuth.EmailChangeTokenExpireDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if err := u.emailTask.QueueEmailChangeConfirmation(ctx, &payload.EmailChangeConfirmationPayload{
+		Email: emailChange.NewEmail,
+		Token: emailChange.Token.String(),
+	}); err != nil {
+		return err
+	}
+
+	return u.emailTask.QueueEmailChangeNotice(ctx, &payload.EmailChangeNoticePayload{
+		Email:    userDb.Email,
+		NewEmail: emailChange.NewEmail,
+	})
+}
+
+func (u *authUsecaseImpl) ConfirmEmailChange(ctx context.Context, req *dto_request.ConfirmEmailChange) error {
+	token, err := u.base64Encryptor.DecodeURL(req.Token)
+	if err != nil {
+		return apperrorAuth.NewInvalidEmailChangeTokenError()
+	}
+
+	parseToken, err := uuid.Parse(token)
+	if err != nil {
+		return apperrorAuth.NewInvalidEmailChangeTokenError()
+	}
+
+	val, err := u.redisUtil.GetDel(ctx, utils.EmailChangeKey(parseToken))
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if val == "" {
+		return apperrorAuth.NewInvalidEmailChangeTokenError()
+	}
+
+	emailChange := new(entity.EmailChange)
+	if err := json.Unmarshal([]byte(val), emailChange); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	err = u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		userDb, err := u.userRepo.Find(txCtx, "id", emailChange.UserID)
+		if err != nil {
+			return err
+		}
+
+		unclaimed, err := u.userIdentityRepo.FindUnclaimedByUserID(txCtx, userDb.ID)
+		if err != nil {
+			return err
+		}
+		if unclaimed != nil {
+			return apperrorAuth.NewOauthEmailChangeError()
+		}
+
+		checkEmail, err := u.userRepo.Find(txCtx, "email", emailChange.NewEmail)
+		if err != nil && err != apperrorPkg.NewNoRowsError(err, emailChange.NewEmail).OriginalError() {
+			return err
+		}
+		if checkEmail != nil {
+			return apperrorAuth.NewInvalidEmailAlreadyExists(nil)
+		}
+
+		previousEmail := userDb.Email
+		userDb.Email = emailChange.NewEmail
+		userDb.UpdatedBy = &userDb.ID
+		if err := u.userRepo.UpdateEmail(txCtx, userDb); err != nil {
+			// another account may have claimed the address since the check above
+			if appErr, ok := err.(*apperrorPkg.AppError); ok && database.IsUniqueViolation(appErr.OriginalError()) {
+				return apperrorAuth.NewInvalidEmailAlreadyExists(err)
+			}
+			return err
+		}
+
+		// reset links are looked up by email, any pending one went to the old address
+		if err := u.resetTokenRepo.DeleteByUserID(txCtx, userDb.ID); err != nil {
+			return err
+		}
+
+		if err := u.auditEventUsecase.Record(txCtx, userDb.ID, constantAuth.AuditEventEmailChanged, &previousEmail); err != nil {
+			return err
+		}
+
+		// only the session that asked for the change stays signed in
+		return transactor.AfterCommit(txCtx, func(ctx context.Context) error {
+			return u.sessionUsecase.RevokeOthers(ctx, userDb.ID, emailChange.SessionID)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := u.redisUtil.Delete(ctx, utils.UserEmailChangeKey(emailChange.UserID)); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
 func (u *authUsecaseImpl) InactiveAccount(ctx context.Context, req *dto_request.InactiveAccount) error {
 	err := u.transactor.Atomic(ctx, func(txCtx context.Context) error {
 		userDb, err := u.userRepo.Find(txCtx, "id", req.UserID)
@@ -486,15 +895,154 @@ func (u *authUsecaseImpl) InactiveAccount(ctx context.Context, req *dto_request.
 			return err
 		}
 
-		userDb.IsActive = false
-		userDb.UpdatedBy = &req.UpdatedBy
+		if userDb.Status.IsUserStatusDeactivated() {
+			return nil
+		}
+
+		return u.accountStatusUsecase.Transition(txCtx, userDb, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusDeactivated,
+			ActorID: req.UpdatedBy,
+		})
+	})
+
+	return err
+}
+
+func (u *authUsecaseImpl) SuspendAccount(ctx context.Context, req *dto_request.SuspendAccount) error {
+	return u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		userDb, err := u.userRepo.Find(txCtx, "id", req.UserID)
+		if err != nil {
+			return err
+		}
 
-		err = u.userRepo.Update(txCtx, userDb)
+		if userDb.ID == req.UpdatedBy {
+			return apperrorPkg.NewDontHavePermissionErrorMessageError()
+		}
+
+		return u.accountStatusUsecase.Transition(txCtx, userDb, &entity.StatusChange{
+			To:             custom_typeAuth.UserStatusSuspended,
+			ActorID:        req.UpdatedBy,
+			Reason:         &req.Reason,
+			SuspendedUntil: req.Until,
+		})
+	})
+}
+
+func (u *authUsecaseImpl) ActivateAccount(ctx context.Context, req *dto_request.ActivateAccount) error {
+	return u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		userDb, err := u.userRepo.Find(txCtx, "id", req.UserID)
 		if err != nil {
 			return err
 		}
+
+		if userDb.Status.IsUserStatusActive() {
+			return nil
+		}
+
+		return u.accountStatusUsecase.Transition(txCtx, userDb, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusActive,
+			ActorID: req.UpdatedBy,
+		})
+	})
+}
+
+// RequestReactivation only mails a link to users who switched their account
+// off or asked for its deletion themselves, everyone else gets the same answer
+// without an email.
+func (u *authUsecaseImpl) RequestReactivation(ctx context.Context, req *dto_request.RequestReactivation) error {
+	email := normalizeLoginEmail(req.Email)
+
+	cachedReactivation := new(entity.Reactivation)
+	if err := u.redisUtil.GetWithScanJSON(ctx, utils.ReactivationCacheKey(email), cachedReactivation); err == nil {
+		if time.Since(cachedReactivation.CreatedAt) < constantAuth.ReactivationTokenCooldownDuration {
+			return apperrorAuth.NewTokenAlreadyExistsError()
+		}
+	}
+
+	reactivation := &entity.Reactivation{
+		Token:     uuid.New(),
+		CreatedAt: time.Now(),
+	}
+
+	if err := u.redisUtil.SetJSON(ctx, utils.ReactivationCacheKey(email), reactivation, constantAuth.ReactivationTokenCooldownDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	userDb, err := u.userRepo.Find(ctx, "email", req.Email)
+	if err != nil && err != apperrorPkg.NewNoRowsError(err, req.Email).OriginalError() {
+		return err
+	}
+	if userDb == nil || !canSelfReactivate(userDb) {
 		return nil
+	}
+
+	reactivation.UserID = userDb.ID
+	if err := u.redisUtil.SetJSON(ctx, utils.ReactivationTokenKey(reactivation.Token), reactivation, constantAuth.ReactivationTokenExpireDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return u.emailTask.QueueReactivationEmail(ctx, &payload.ReactivationEmailPayload{
+		Email: userDb.Email,
+		Token: reactivation.Token.String(),
 	})
+}
 
-	return err
+func (u *authUsecaseImpl) ConfirmReactivation(ctx context.Context, req *dto_request.ConfirmReactivation) error {
+	token, err := u.base64Encryptor.DecodeURL(req.Token)
+	if err != nil {
+		return apperrorAuth.NewInvalidReactivationTokenError()
+	}
+
+	parseToken, err := uuid.Parse(token)
+	if err != nil {
+		return apperrorAuth.NewInvalidReactivationTokenError()
+	}
+
+	val, err := u.redisUtil.GetDel(ctx, utils.ReactivationTokenKey(parseToken))
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if val == "" {
+		return apperrorAuth.NewInvalidReactivationTokenError()
+	}
+
+	reactivation := new(entity.Reactivation)
+	if err := json.Unmarshal([]byte(val), reactivation); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		userDb, err := u.userRepo.Find(txCtx, "id", reactivation.UserID)
+		if err != nil {
+			return err
+		}
+
+		if userDb.Status.IsUserStatusActive() {
+			return nil
+		}
+
+		// an admin may have taken over the deactivation since the link was sent
+		if !canSelfReactivate(userDb) {
+			return apperrorAuth.NewReactivationNotAllowedError()
+		}
+
+		return u.accountStatusUsecase.Transition(txCtx, userDb, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusActive,
+			ActorID: userDb.ID,
+		})
+	})
+}
+
+// canSelfReactivate also covers an account waiting for deletion, reactivating
+// it is how the deletion gets cancelled within the grace period.
+func canSelfReactivate(user *entity.User) bool {
+	if !user.Status.IsUserStatusDeactivated() && !user.Status.IsUserStatusPendingDeletion() {
+		return false
+	}
+	return user.StatusChangedBy != nil && *user.StatusChangedBy == user.ID
+}
+
+func (u *authUsecaseImpl) UnlockAccount(ctx context.Context, userID uuid.UUID) error {
+	return u.loginAttemptUsecase.Unlock(ctx, userID)
 }
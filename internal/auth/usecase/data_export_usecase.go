@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	converterAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/converter"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	dto_response "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/response"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/tasks"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	constantPkg "github.com/faisalyudiansah/auth-service-template/pkg/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/storageutils"
+
+	"github.com/google/uuid"
+)
+
+type DataExportUsecase interface {
+	Request(ctx context.Context, req *dto_request.RequestDataExport) error
+	Build(ctx context.Context, req *dto_request.RequestDataExport) error
+	Cleanup(ctx context.Context, storageKey string) error
+	Download(ctx context.Context, req *dto_request.DownloadDataExport) (*entity.DataExport, []byte, error)
+}
+
+type dataExportUsecaseImpl struct {
+	userRepo               repository.UserRepository
+	userDetailRepo         repository.UserDetailRepository
+	userIdentityRepo       repository.UserIdentityRepository
+	sessionRepo            repository.SessionRepository
+	userStatusHistoryRepo  repository.UserStatusHistoryRepository
+	passwordHistoryRepo    repository.PasswordHistoryRepository
+	userTOTPRepo           repository.UserTOTPRepository
+	webAuthnCredentialRepo repository.WebAuthnCredentialRepository
+	verificationTokenRepo  repository.VerificationTokenRepository
+	resetTokenRepo         repository.ResetTokenRepository
+	auditEventUsecase      AuditEventUsecase
+	redisUtil              redisutils.RedisUtil
+	base64Encryptor        encryptutils.Base64Encryptor
+	storage                storageutils.Storage
+	dataExportTask         tasks.DataExportTask
+	emailTask              tasks.EmailTask
+	cfg                    *config.Config
+}
+
+func NewDataExportUsecase(
+	userRepo repository.UserRepository,
+	userDetailRepo repository.UserDetailRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	sessionRepo repository.SessionRepository,
+	userStatusHistoryRepo repository.UserStatusHistoryRepository,
+	passwordHistoryRepo repository.PasswordHistoryRepository,
+	userTOTPRepo repository.UserTOTPRepository,
+	webAuthnCredentialRepo repository.WebAuthnCredentialRepository,
+	verificationTokenRepo repository.VerificationTokenRepository,
+	resetTokenRepo repository.ResetTokenRepository,
+	auditEventUsecase AuditEventUsecase,
+	redisUtil redisutils.RedisUtil,
+	base64Encryptor encryptutils.Base64Encryptor,
+	storage storageutils.Storage,
+	dataExportTask tasks.DataExportTask,
+	emailTask tasks.EmailTask,
+	cfg *config.Config,
+) *dataExportUsecaseImpl {
+	return &dataExportUsecaseImpl{
+		userRepo:               userRepo,
+		userDetailRepo:         userDetailRepo,
+		userIdentityRepo:       userIdentityRepo,
+		sessionRepo:            sessionRepo,
+		userStatusHistoryRepo:  userStatusHistoryRepo,
+		passwordHistoryRepo:    passwordHistoryRepo,
+		userTOTPRepo:           userTOTPRepo,
+		webAuthnCredentialRepo: webAuthnCredentialRepo,
+		verificationTokenRepo:  verificationTokenRepo,
+		resetTokenRepo:         resetTokenRepo,
+		auditEventUsecase:      auditEventUsecase,
+		redisUtil:              redisUtil,
+		base64Encryptor:        base64Encryptor,
+		storage:                storage,
+		dataExportTask:         dataExportTask,
+		emailTask:              emailTask,
+		cfg:                    cfg,
+	}
+}
+
+func (u *dataExportUsecaseImpl) linkExpiry() time.Duration {
+	return time.Duration(u.cfg.DataExport.LinkExpireHours) * time.Hour
+}
+
+// Request only queues the export, gathering everything is left to the worker.
+func (u *dataExportUsecaseImpl) Request(ctx context.Context, req *dto_request.RequestDataExport) error {
+	if _, err := u.userRepo.Find(ctx, "id", req.UserID); err != nil {
+		return err
+	}
+
+	cooldown, err := u.redisUtil.Get(ctx, utils.UserDataExportKey(req.UserID))
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+	if cooldown != "" {
+		return apperrorAuth.NewDataExportAlreadyRequestedError()
+	}
+
+	if req.Format == "" {
+		req.Format = constantAuth.DataExportFormatJSON
+	}
+
+	if err := u.dataExportTask.QueueBuild(ctx, &payload.DataExportPayload{
+		UserID:      req.UserID,
+		RequestedBy: req.RequestedBy,
+		Format:      req.Format,
+	}); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	if err := u.redisUtil.Set(ctx, utils.UserDataExportKey(req.UserID), req.RequestedBy.String(), constantAuth.DataExportCooldownDuration); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return u.auditEventUsecase.Record(ctx, req.UserID, constantAuth.AuditEventDataExportRequested, &req.Format)
+}
+
+// Build stores the archive and mails a download link to whoever asked for
+// it, an administrator exporting a user gets the link instead of the user.
+func (u *dataExportUsecaseImpl) Build(ctx context.Context, req *dto_request.RequestDataExport) error {
+	archive, err := u.collect(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	requester := archive.User
+	if req.RequestedBy != req.UserID {
+		requester, err = u.userRepo.Find(ctx, "id", req.RequestedBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, fileName, contentType, err := encodeDataExport(converterAuth.DataExportArchiveEntityToDTOResponse(archive), req.Format)
+	if err != nil {
+		return err
+	}
+
+	export := &entity.DataExport{
+		ID:          uuid.New(),
+		UserID:      req.UserID,
+		RequestedBy: req.RequestedBy,
+		FileName:    fileName,
+		ContentType: contentType,
+		CreatedAt:   archive.GeneratedAt,
+	}
+	export.StorageKey = fmt.Sprintf("%v/%v/%v", constantAuth.DATA_EXPORT, req.UserID, export.ID)
+
+	if err := u.storage.Put(ctx, export.StorageKey, data); err != nil {
+		return err
+	}
+
+	if err := u.dataExportTask.QueueCleanup(ctx, &payload.DataExportCleanupPayload{StorageKey: export.StorageKey}, u.linkExpiry()); err != nil {
+		return err
+	}
+
+	token := uuid.New()
+	if err := u.redisUtil.SetJSON(ctx, utils.DataExportTokenKey(token), export, u.linkExpiry()); err != nil {
+		return err
+	}
+
+	return u.emailTask.QueueDataExportEmail(ctx, &payload.DataExportEmailPayload{
+		Email:    requester.Email,
+		Token:    token.String(),
+		ExpireAt: export.CreatedAt.Add(u.linkExpiry()).Format(time.RFC1123),
+	})
+}
+
+func (u *dataExportUsecaseImpl) Cleanup(ctx context.Context, storageKey string) error {
+	return u.storage.Delete(ctx, storageKey)
+}
+
+// Download only hands the archive to the signed in user who asked for it, the
+// emailed token alone is not enough.
+func (u *dataExportUsecaseImpl) Download(ctx context.Context, req *dto_request.DownloadDataExport) (*entity.DataExport, []byte, error) {
+	token, err := u.base64Encryptor.DecodeURL(req.Token)
+	if err != nil {
+		return nil, nil, apperrorAuth.NewInvalidDataExportLinkError()
+	}
+
+	parseToken, err := uuid.Parse(token)
+	if err != nil {
+		return nil, nil, apperrorAuth.NewInvalidDataExportLinkError()
+	}
+
+	export := new(entity.DataExport)
+	if err := u.redisUtil.GetWithScanJSON(ctx, utils.DataExportTokenKey(parseToken), export); err != nil {
+		return nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	if export.ID == uuid.Nil || export.RequestedBy != req.UserID {
+		return nil, nil, apperrorAuth.NewInvalidDataExportLinkError()
+	}
+
+	data, err := u.storage.Get(ctx, export.StorageKey)
+	if err == storageutils.ErrObjectNotFound {
+		return nil, nil, apperrorAuth.NewInvalidDataExportLinkError()
+	}
+	if err != nil {
+		return nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	return export, data, nil
+}
+
+func (u *dataExportUsecaseImpl) collect(ctx context.Context, userID uuid.UUID) (*entity.DataExportArchive, error) {
+	archive := &entity.DataExportArchive{
+		GeneratedAt: time.Now(),
+	}
+
+	user, err := u.userRepo.Find(ctx, "id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userDetail, err := u.userDetailRepo.Find(ctx, "user_id", userID)
+	if err != nil && err != apperrorPkg.NewNoRowsError(err, userID).OriginalError() {
+		return nil, err
+	}
+	user.UserDetail = userDetail
+	archive.User = user
+
+	userTOTP, err := u.userTOTPRepo.FindByUserID(ctx, userID)
+	if err != nil && err != apperrorPkg.NewNoRowsError(err, userID).OriginalError() {
+		return nil, err
+	}
+	archive.TOTP = userTOTP
+
+	if archive.Identities, err = u.userIdentityRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.Passkeys, err = u.webAuthnCredentialRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.Sessions, err = u.sessionRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.StatusHistory, err = u.userStatusHistoryRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.AuditEvents, err = u.auditEventUsecase.GetListByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.PasswordHistory, err = u.passwordHistoryRepo.FindByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.VerificationTokens, err = u.verificationTokenRepo.FindHistoryByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if archive.ResetTokens, err = u.resetTokenRepo.FindHistoryByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func encodeDataExport(doc *dto_response.DataExport, format string) ([]byte, string, string, error) {
+	fileName := fmt.Sprintf("data-export-%v", doc.GeneratedAt.Format("20060102150405"))
+
+	if format != constantAuth.DataExportFormatCSV {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		return data, fileName + ".json", "application/json", err
+	}
+
+	data, err := encodeDataExportCSV(doc)
+	return data, fileName + ".zip", "application/zip", err
+}
+
+// encodeDataExportCSV writes one csv file per section into a zip archive.
+func encodeDataExportCSV(doc *dto_response.DataExport) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+
+	user := [][]string{{"id", "email", "role", "status", "full_name", "sex", "phone_number", "phone_verified_at", "birth_date", "image_url", "created_at"}}
+	if doc.User != nil {
+		row := []string{doc.User.ID.String(), doc.User.Email, doc.User.RoleLabel, doc.User.StatusLabel, "", "", "", "", "", "", formatCSVTime(&doc.User.CreatedAt)}
+		if detail := doc.User.UserDetail; detail != nil {
+			row[4], row[5] = detail.FullName, detail.SexLabel
+			if detail.PhoneNumber != nil {
+				row[6] = *detail.PhoneNumber
+			}
+			row[7] = formatCSVTime(detail.PhoneVerifiedAt)
+			row[8] = detail.BirthDate.Format(constantPkg.DEFAULT_DATE_ONLY)
+			row[9] = detail.ImageURL
+		}
+		user = append(user, row)
+	}
+
+	identities := [][]string{{"id", "provider", "email", "created_at", "last_used_at"}}
+	for _, item := range doc.Identities {
+		identities = append(identities, []string{item.ID.String(), item.Provider, item.Email, formatCSVTime(&item.CreatedAt), formatCSVTime(item.LastUsedAt)})
+	}
+
+	twoFactor := [][]string{{"enabled", "confirmed_at"}, {strconv.FormatBool(doc.TwoFactor.Enabled), formatCSVTime(doc.TwoFactor.ConfirmedAt)}}
+
+	passkeys := [][]string{{"id", "name", "created_at", "last_used_at"}}
+	for _, item := range doc.Passkeys {
+		passkeys = append(passkeys, []string{item.ID.String(), item.Name, formatCSVTime(&item.CreatedAt), formatCSVTime(item.LastUsedAt)})
+	}
+
+	sessions := [][]string{{"session_id", "login_method", "client_ip", "user_agent", "browser", "os", "device_type", "login_at", "last_refresh_at", "last_seen_at"}}
+	for _, item := range doc.Sessions {
+		sessions = append(sessions, []string{item.SessionID.String(), item.LoginMethod, item.ClientIP, item.UserAgent, item.Browser, item.OS, item.DeviceType, formatCSVTime(&item.LoginAt), formatCSVTime(item.LastRefreshAt), formatCSVTime(item.LastSeenAt)})
+	}
+
+	statusHistory := [][]string{{"from_status", "to_status", "reason", "suspended_until", "actor_id", "created_at"}}
+	for _, item := range doc.StatusHistory {
+		reason := ""
+		if item.Reason != nil {
+			reason = *item.Reason
+		}
+		statusHistory = append(statusHistory, []string{item.FromStatusLabel, item.ToStatusLabel, reason, formatCSVTime(item.SuspendedUntil), item.ActorID.String(), formatCSVTime(&item.CreatedAt)})
+	}
+
+	auditEvents := [][]string{{"event", "detail", "actor_id", "client_ip", "user_agent", "created_at"}}
+	for _, item := range doc.AuditEvents {
+		detail := ""
+		if item.Detail != nil {
+			detail = *item.Detail
+		}
+		auditEvents = append(auditEvents, []string{item.Event, detail, item.ActorID.String(), item.ClientIP, item.UserAgent, formatCSVTime(&item.CreatedAt)})
+	}
+
+	passwordChanges := [][]string{{"changed_at"}}
+	for _, item := range doc.PasswordChanges {
+		passwordChanges = append(passwordChanges, []string{formatCSVTime(&item)})
+	}
+
+	tokenHistory := [][]string{{"type", "created_at", "invalidated_at"}}
+	for _, item := range doc.TokenHistory {
+		tokenHistory = append(tokenHistory, []string{item.Type, formatCSVTime(&item.CreatedAt), formatCSVTime(item.InvalidatedAt)})
+	}
+
+	files := []struct {
+		name string
+		rows [][]string
+	}{
+		{"user.csv", user},
+		{"identities.csv", identities},
+		{"two_factor.csv", twoFactor},
+		{"passkeys.csv", passkeys},
+		{"sessions.csv", sessions},
+		{"status_history.csv", statusHistory},
+		{"audit_events.csv", auditEvents},
+		{"password_changes.csv", passwordChanges},
+		{"token_history.csv", tokenHistory},
+	}
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := csv.NewWriter(w).WriteAll(file.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
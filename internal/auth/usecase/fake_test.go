@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/totputils"
+
+	"github.com/google/uuid"
+)
+
+// fakeRedisUtil keeps values in memory. Expiry is only recorded, tests that
+// care about it read ttls. Methods that are not implemented panic through
+// the embedded nil interface.
+type fakeRedisUtil struct {
+	redisutils.RedisUtil
+
+	mu     sync.Mutex
+	values map[string]string
+	sets   map[string]map[string]struct{}
+	ttls   map[string]time.Duration
+}
+
+func newFakeRedisUtil() *fakeRedisUtil {
+	return &fakeRedisUtil{
+		values: map[string]string{},
+		sets:   map[string]map[string]struct{}{},
+		ttls:   map[string]time.Duration{},
+	}
+}
+
+func (r *fakeRedisUtil) Set(ctx context.Context, key string, value any, duration time.Duration) error {
+	data, ok := value.(string)
+	if !ok {
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		data = string(bytes)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values[key] = data
+	r.ttls[key] = duration
+
+	return nil
+}
+
+func (r *fakeRedisUtil) SetJSON(ctx context.Context, key string, value any, duration time.Duration) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return r.Set(ctx, key, string(bytes), duration)
+}
+
+func (r *fakeRedisUtil) Get(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.values[key], nil
+}
+
+func (r *fakeRedisUtil) GetDel(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	val := r.values[key]
+	delete(r.values, key)
+	delete(r.ttls, key)
+
+	return val, nil
+}
+
+func (r *fakeRedisUtil) GetWithScanJSON(ctx context.Context, key string, dest any) error {
+	val, _ := r.Get(ctx, key)
+	if val == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(val), dest)
+}
+
+func (r *fakeRedisUtil) Delete(ctx context.Context, keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		delete(r.values, key)
+		delete(r.sets, key)
+		delete(r.ttls, key)
+	}
+
+	return nil
+}
+
+func (r *fakeRedisUtil) SAdd(ctx context.Context, key string, members ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sets[key] == nil {
+		r.sets[key] = map[string]struct{}{}
+	}
+	for _, member := range members {
+		r.sets[key][member] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *fakeRedisUtil) SMembers(ctx context.Context, key string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members := []string{}
+	for member := range r.sets[key] {
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+func (r *fakeRedisUtil) SRem(ctx context.Context, key string, members ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, member := range members {
+		delete(r.sets[key], member)
+	}
+
+	return nil
+}
+
+func (r *fakeRedisUtil) Expire(ctx context.Context, key string, duration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ttls[key] = duration
+
+	return nil
+}
+
+func (r *fakeRedisUtil) Incr(ctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count, _ := strconv.ParseInt(r.values[key], 10, 64)
+	count++
+	r.values[key] = strconv.FormatInt(count, 10)
+
+	return count, nil
+}
+
+func (r *fakeRedisUtil) IncrWithExpire(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	count, err := r.Incr(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 1 {
+		return count, r.Expire(ctx, key, duration)
+	}
+
+	return count, nil
+}
+
+func (r *fakeRedisUtil) TTL(ctx context.Context, key string) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ttls[key], nil
+}
+
+func (r *fakeRedisUtil) CompareAndSetJSON(ctx context.Context, key, field, expected string, value any, duration time.Duration) (bool, error) {
+	r.mu.Lock()
+	current, ok := r.values[key]
+	r.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(current), &fields); err != nil {
+		return false, err
+	}
+
+	if fields[field] != expected {
+		return false, nil
+	}
+
+	return true, r.SetJSON(ctx, key, value, duration)
+}
+
+func (r *fakeRedisUtil) SetWithIndex(ctx context.Context, key string, value any, duration time.Duration, indexKey, member string) error {
+	if err := r.Set(ctx, key, value, duration); err != nil {
+		return err
+	}
+
+	if err := r.SAdd(ctx, indexKey, member); err != nil {
+		return err
+	}
+
+	if ttl, _ := r.TTL(ctx, indexKey); ttl < duration {
+		return r.Expire(ctx, indexKey, duration)
+	}
+
+	return nil
+}
+
+func (r *fakeRedisUtil) DeleteIndex(ctx context.Context, indexKey, keyPrefix string) error {
+	members, _ := r.SMembers(ctx, indexKey)
+
+	keys := []string{indexKey}
+	for _, member := range members {
+		keys = append(keys, keyPrefix+member)
+	}
+
+	return r.Delete(ctx, keys...)
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+
+	users         map[uuid.UUID]*entity.User
+	statusUpdates int
+}
+
+func newFakeUserRepository(users ...*entity.User) *fakeUserRepository {
+	repo := &fakeUserRepository{
+		users: map[uuid.UUID]*entity.User{},
+	}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+
+	return repo
+}
+
+func (r *fakeUserRepository) Find(ctx context.Context, field string, value any) (*entity.User, error) {
+	for _, user := range r.users {
+		if (field == "id" && user.ID == value) || (field == "email" && user.Email == value) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *fakeUserRepository) UpdateStatus(ctx context.Context, user *entity.User) error {
+	r.statusUpdates++
+	copied := *user
+	r.users[user.ID] = &copied
+
+	return nil
+}
+
+type fakeUserStatusHistoryRepository struct {
+	repository.UserStatusHistoryRepository
+
+	saved []*entity.UserStatusHistory
+}
+
+func (r *fakeUserStatusHistoryRepository) Save(ctx context.Context, history *entity.UserStatusHistory) error {
+	r.saved = append(r.saved, history)
+
+	return nil
+}
+
+type fakeSessionUsecase struct {
+	SessionUsecase
+
+	revokedAll []uuid.UUID
+}
+
+func (u *fakeSessionUsecase) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	u.revokedAll = append(u.revokedAll, userID)
+
+	return nil
+}
+
+type fakeAuditEventUsecase struct {
+	AuditEventUsecase
+
+	events []string
+}
+
+func (u *fakeAuditEventUsecase) Record(ctx context.Context, userID uuid.UUID, event string, detail *string) error {
+	u.events = append(u.events, event)
+
+	return nil
+}
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		Jwt: &config.JwtConfig{
+			AllowedAlgs:     []string{"HS256"},
+			Issuer:          "http://localhost:8000",
+			SecretKey:       "test-secret",
+			TokenDuration:   15,
+			RefreshDuration: 60,
+		},
+		Session: &config.SessionConfig{
+			IdleTimeout: 60,
+		},
+		OIDC: &config.OIDCConfig{
+			Issuer:  "http://localhost:8000",
+			Clients: map[string]*config.OIDCClient{},
+		},
+	}
+}
+
+func newTestSessionUsecase(cfg *config.Config, users ...*entity.User) (*sessionUsecaseImpl, *fakeRedisUtil) {
+	redisUtil := newFakeRedisUtil()
+	sessionUsecase := NewSessionUsecase(
+		newFakeUserRepository(users...),
+		repository.NewSessionRepository(redisUtil),
+		&fakeAuditEventUsecase{},
+		jwtutils.NewJwtUtil(cfg.Jwt),
+		cfg,
+	)
+
+	return sessionUsecase, redisUtil
+}
+
+type fakeUserDetailRepository struct {
+	repository.UserDetailRepository
+}
+
+func (r *fakeUserDetailRepository) Find(ctx context.Context, field string, value any) (*entity.UserDetail, error) {
+	userID, _ := value.(uuid.UUID)
+
+	return &entity.UserDetail{UserID: userID, FullName: "Test User"}, nil
+}
+
+type fakeUserTOTPRepository struct {
+	repository.UserTOTPRepository
+
+	totps map[uuid.UUID]*entity.UserTOTP
+}
+
+func newFakeUserTOTPRepository(totps ...*entity.UserTOTP) *fakeUserTOTPRepository {
+	repo := &fakeUserTOTPRepository{
+		totps: map[uuid.UUID]*entity.UserTOTP{},
+	}
+	for _, totp := range totps {
+		repo.totps[totp.UserID] = totp
+	}
+
+	return repo
+}
+
+func (r *fakeUserTOTPRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error) {
+	totp, ok := r.totps[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *totp
+	return &copied, nil
+}
+
+func (r *fakeUserTOTPRepository) UpdateLastUsedStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	for _, totp := range r.totps {
+		if totp.ID == id && step > totp.LastUsedStep {
+			totp.LastUsedStep = step
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *fakeUserTOTPRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	delete(r.totps, userID)
+
+	return nil
+}
+
+// fakeTOTPUtil accepts a single code, always for the same time step, so a
+// second use of it is a replay.
+type fakeTOTPUtil struct {
+	totputils.TOTPUtil
+
+	code string
+	step int64
+}
+
+func (u *fakeTOTPUtil) Validate(secret, code string, currentTime time.Time) (int64, bool) {
+	return u.step, code == u.code
+}
+
+// fakeAESEncryptor leaves the plaintext as it is.
+type fakeAESEncryptor struct {
+	encryptutils.AESEncryptor
+}
+
+func (e *fakeAESEncryptor) Encrypt(plaintext string) (string, error) {
+	return plaintext, nil
+}
+
+func (e *fakeAESEncryptor) Decrypt(ciphertext string) (string, error) {
+	return ciphertext, nil
+}
+
+// fakeTransactor runs the function without a transaction.
+type fakeTransactor struct{}
+
+func (t *fakeTransactor) Atomic(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/oauthutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+
+	"github.com/google/uuid"
+)
+
+type IdentityUsecase interface {
+	GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error)
+	BeginLink(ctx context.Context, req *dto_request.LinkIdentity) (*entity.OauthLink, error)
+	Unlink(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type identityUsecaseImpl struct {
+	userRepo               repository.UserRepository
+	userIdentityRepo       repository.UserIdentityRepository
+	webAuthnCredentialRepo repository.WebAuthnCredentialRepository
+	auditEventUsecase      AuditEventUsecase
+	redisUtil              redisutils.RedisUtil
+	transactor             transactor.Transactor
+	cfg                    *config.Config
+}
+
+func NewIdentityUsecase(
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	webAuthnCredentialRepo repository.WebAuthnCredentialRepository,
+	auditEventUsecase AuditEventUsecase,
+	redisUtil redisutils.RedisUtil,
+	transactor transactor.Transactor,
+	cfg *config.Config,
+) *identityUsecaseImpl {
+	return &identityUsecaseImpl{
+		userRepo:               userRepo,
+		userIdentityRepo:       userIdentityRepo,
+		webAuthnCredentialRepo: webAuthnCredentialRepo,
+		auditEventUsecase:      auditEventUsecase,
+		redisUtil:              redisUtil,
+		transactor:             transactor,
+		cfg:                    cfg,
+	}
+}
+
+func (u *identityUsecaseImpl) GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error) {
+	return u.userIdentityRepo.FindByUserID(ctx, userID)
+}
+
+// BeginLink hands out the provider login url, its state marks the callback
+// as a link for this user instead of a login.
+func (u *identityUsecaseImpl) BeginLink(ctx context.Context, req *dto_request.LinkIdentity) (*entity.OauthLink, error) {
+	if !oauthutils.IsEnabled(req.Provider) {
+		return nil, apperrorAuth.NewUnsupportedOauthProviderError(req.Provider)
+	}
+
+	link := &entity.OauthLink{
+		State:     uuid.New(),
+		UserID:    req.UserID,
+		Provider:  req.Provider,
+		CreatedAt: time.Now(),
+	}
+
+	if err := u.redisUtil.SetJSON(ctx, utils.OauthLinkKey(link.State.String()), link, constantAuth.OauthLinkExpireDuration); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	link.AuthURL = fmt.Sprintf("%s/oauth/%s/login?state=%s", u.cfg.OIDC.Issuer, url.PathEscape(link.Provider), link.State)
+
+	return link, nil
+}
+
+func (u *identityUsecaseImpl) Unlink(ctx context.Context, userID, id uuid.UUID) error {
+	return u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		// two unlinks running side by side would both count the other's
+		// identity as a login method left
+		if err := u.userRepo.LockByID(txCtx, userID); err != nil {
+			return err
+		}
+
+		identity, err := u.userIdentityRepo.FindByID(txCtx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		if identity == nil {
+			return apperrorAuth.NewIdentityNotFoundError()
+		}
+
+		loginMethods, err := u.countLoginMethods(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		if loginMethods <= 1 {
+			return apperrorAuth.NewLastLoginMethodError()
+		}
+
+		if err := u.userIdentityRepo.Delete(txCtx, id, userID); err != nil {
+			return err
+		}
+
+		return u.auditEventUsecase.Record(txCtx, userID, constantAuth.AuditEventIdentityUnlinked, &identity.Provider)
+	})
+}
+
+// countLoginMethods counts the ways a user can sign in without help: a
+// password, each linked identity and each passkey.
+func (u *identityUsecaseImpl) countLoginMethods(ctx context.Context, userID uuid.UUID) (int, error) {
+	user, err := u.userRepo.Find(ctx, "id", userID)
+	if err != nil {
+		return 0, err
+	}
+
+	identities, err := u.userIdentityRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	passkeys, err := u.webAuthnCredentialRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := len(identities) + len(passkeys)
+	if user.HashPassword != "" {
+		count++
+	}
+
+	return count, nil
+}
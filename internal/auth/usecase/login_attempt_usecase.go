@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+	"github.com/faisalyudiansah/auth-service-template/pkg/metrics"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+
+	"github.com/google/uuid"
+)
+
+type LoginAttemptUsecase interface {
+	Check(ctx context.Context, email, clientIP string) error
+	RecordFailure(ctx context.Context, email, clientIP string) error
+	RecordSuccess(ctx context.Context, email, clientIP string) error
+	Unlock(ctx context.Context, userID uuid.UUID) error
+}
+
+type loginAttemptUsecaseImpl struct {
+	userRepo  repository.UserRepository
+	redisUtil redisutils.RedisUtil
+	cfg       *config.Config
+}
+
+func NewLoginAttemptUsecase(
+	userRepo repository.UserRepository,
+	redisUtil redisutils.RedisUtil,
+	cfg *config.Config,
+) *loginAttemptUsecaseImpl {
+	return &loginAttemptUsecaseImpl{
+		userRepo:  userRepo,
+		redisUtil: redisUtil,
+		cfg:       cfg,
+	}
+}
+
+// Check rejects the attempt while the email or the client IP is locked out or
+// still has to wait for its progressive delay to pass.
+func (u *loginAttemptUsecaseImpl) Check(ctx context.Context, email, clientIP string) error {
+	for _, attempt := range u.scopes(email, clientIP) {
+		scope, value := attempt.scope, attempt.value
+
+		lockTTL, err := u.redisUtil.TTL(ctx, utils.LoginLockKey(scope, value))
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		if lockTTL > 0 {
+			if scope == constantAuth.LoginAttemptScopeEmail {
+				return apperrorAuth.NewAccountLockedError(lockTTL)
+			}
+			return apperrorAuth.NewTooManyLoginAttemptsError(lockTTL)
+		}
+
+		delayTTL, err := u.redisUtil.TTL(ctx, utils.LoginDelayKey(scope, value))
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		if delayTTL > 0 {
+			return apperrorAuth.NewTooManyLoginAttemptsError(delayTTL)
+		}
+	}
+
+	return nil
+}
+
+func (u *loginAttemptUsecaseImpl) RecordFailure(ctx context.Context, email, clientIP string) error {
+	loginProtection := u.cfg.LoginProtection
+	failureWindow := time.Duration(loginProtection.FailureWindowMinute) * time.Minute
+	lockoutDuration := time.Duration(loginProtection.LockoutDurationMinute) * time.Minute
+
+	if clientIP != "" {
+		// remembers which addresses failed against the email, an admin unlock
+		// has no client IP of its own to clear
+		failedIPsKey := utils.LoginFailedIPsKey(normalizeLoginEmail(email))
+		if err := u.redisUtil.SAdd(ctx, failedIPsKey, clientIP); err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		if err := u.redisUtil.Expire(ctx, failedIPsKey, max(failureWindow, lockoutDuration)); err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+	}
+
+	for _, attempt := range u.scopes(email, clientIP) {
+		scope, value := attempt.scope, attempt.value
+
+		failuresKey := utils.LoginFailuresKey(scope, value)
+
+		failures, err := u.redisUtil.IncrWithExpire(ctx, failuresKey, failureWindow)
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		maxFailures := loginProtection.MaxEmailFailures
+		if scope == constantAuth.LoginAttemptScopeIP {
+			maxFailures = loginProtection.MaxIPFailures
+		}
+
+		if failures >= int64(maxFailures) {
+			if err := u.redisUtil.Set(ctx, utils.LoginLockKey(scope, value), "1", lockoutDuration); err != nil {
+				return apperrorPkg.NewServerError(err)
+			}
+
+			if err := u.redisUtil.Delete(ctx, failuresKey, utils.LoginDelayKey(scope, value)); err != nil {
+				return apperrorPkg.NewServerError(err)
+			}
+
+			metrics.LoginLockouts.WithLabelValues(scope).Inc()
+			logger.FromContext(ctx).WithFields(map[string]any{
+				"scope":    scope,
+				"value":    value,
+				"failures": failures,
+			}).Warn("login locked out after too many failed attempts")
+			continue
+		}
+
+		if failures >= int64(loginProtection.DelayAfterFailures) {
+			// every further failure doubles the wait, capped by the lockout duration
+			delay := time.Duration(loginProtection.DelayBaseSeconds) * time.Second << (failures - int64(loginProtection.DelayAfterFailures))
+			if delay <= 0 || delay > lockoutDuration {
+				delay = lockoutDuration
+			}
+
+			if err := u.redisUtil.Set(ctx, utils.LoginDelayKey(scope, value), "1", delay); err != nil {
+				return apperrorPkg.NewServerError(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *loginAttemptUsecaseImpl) RecordSuccess(ctx context.Context, email, clientIP string) error {
+	keys := []string{}
+	for _, attempt := range u.scopes(email, clientIP) {
+		keys = append(keys,
+			utils.LoginFailuresKey(attempt.scope, attempt.value),
+			utils.LoginDelayKey(attempt.scope, attempt.value),
+		)
+	}
+
+	if err := u.redisUtil.Delete(ctx, keys...); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (u *loginAttemptUsecaseImpl) Unlock(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.userRepo.Find(ctx, "id", userID)
+	if err != nil {
+		return err
+	}
+
+	email := normalizeLoginEmail(user.Email)
+	failedIPsKey := utils.LoginFailedIPsKey(email)
+
+	failedIPs, err := u.redisUtil.SMembers(ctx, failedIPsKey)
+	if err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	scopes := []loginAttemptScope{{scope: constantAuth.LoginAttemptScopeEmail, value: email}}
+	for _, ip := range failedIPs {
+		scopes = append(scopes, loginAttemptScope{scope: constantAuth.LoginAttemptScopeIP, value: ip})
+	}
+
+	keys := []string{failedIPsKey}
+	for _, attempt := range scopes {
+		keys = append(keys,
+			utils.LoginFailuresKey(attempt.scope, attempt.value),
+			utils.LoginDelayKey(attempt.scope, attempt.value),
+			utils.LoginLockKey(attempt.scope, attempt.value),
+		)
+	}
+
+	if err := u.redisUtil.Delete(ctx, keys...); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+type loginAttemptScope struct {
+	scope string
+	value string
+}
+
+// scopes lists the email before the client IP so an account lockout is
+// reported as such even when the IP is throttled as well.
+func (u *loginAttemptUsecaseImpl) scopes(email, clientIP string) []loginAttemptScope {
+	scopes := []loginAttemptScope{
+		{scope: constantAuth.LoginAttemptScopeEmail, value: normalizeLoginEmail(email)},
+	}
+
+	if clientIP != "" {
+		scopes = append(scopes, loginAttemptScope{scope: constantAuth.LoginAttemptScopeIP, value: clientIP})
+	}
+
+	return scopes
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
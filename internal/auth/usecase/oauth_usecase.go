@@ -2,18 +2,21 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
 	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
 	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
 	constantPkg "github.com/faisalyudiansah/auth-service-template/pkg/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database"
 	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
 	custom_typePkg "github.com/faisalyudiansah/auth-service-template/pkg/entity/type"
-	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/oauthutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
 
 	"github.com/google/uuid"
@@ -21,51 +24,145 @@ import (
 )
 
 type OauthUsecase interface {
-	Login(ctx context.Context, request *goth.User) (*entity.User, *entity.Session, error)
+	Login(ctx context.Context, request *goth.User) (*entity.User, *entity.Session, *entity.LoginChallenge, error)
+	Link(ctx context.Context, state string, userID uuid.UUID, request *goth.User) (bool, error)
 }
 
 type oauthUsecaseImpl struct {
-	userRepo       repository.UserRepository
-	userDetailRepo repository.UserDetailRepository
-	redisUtil      redisutils.RedisUtil
-	jwtUtil        jwtutils.JwtUtilInterface
-	transactor     transactor.Transactor
+	userRepo          repository.UserRepository
+	userDetailRepo    repository.UserDetailRepository
+	userIdentityRepo  repository.UserIdentityRepository
+	redisUtil         redisutils.RedisUtil
+	sessionUsecase    SessionUsecase
+	twoFactorUsecase  TwoFactorUsecase
+	auditEventUsecase AuditEventUsecase
+	transactor        transactor.Transactor
 }
 
 func NewOauthUsecase(
 	userRepo repository.UserRepository,
 	userDetailRepo repository.UserDetailRepository,
+	userIdentityRepo repository.UserIdentityRepository,
 	redisUtil redisutils.RedisUtil,
-	jwtUtil jwtutils.JwtUtilInterface,
+	sessionUsecase SessionUsecase,
+	twoFactorUsecase TwoFactorUsecase,
+	auditEventUsecase AuditEventUsecase,
 	transactor transactor.Transactor,
 ) *oauthUsecaseImpl {
 	return &oauthUsecaseImpl{
-		userRepo:       userRepo,
-		userDetailRepo: userDetailRepo,
-		redisUtil:      redisUtil,
-		jwtUtil:        jwtUtil,
-		transactor:     transactor,
+		userRepo:          userRepo,
+		userDetailRepo:    userDetailRepo,
+		userIdentityRepo:  userIdentityRepo,
+		redisUtil:         redisUtil,
+		sessionUsecase:    sessionUsecase,
+		twoFactorUsecase:  twoFactorUsecase,
+		auditEventUsecase: auditEventUsecase,
+		transactor:        transactor,
 	}
 }
 
-func (u *oauthUsecaseImpl) Login(ctx context.Context, request *goth.User) (*entity.User, *entity.Session, error) {
-	currentTime := time.Now()
-	recordUserDB, err := u.userRepo.Find(ctx, "email", request.Email)
+func (u *oauthUsecaseImpl) Login(ctx context.Context, request *goth.User) (*entity.User, *entity.Session, *entity.LoginChallenge, error) {
+	// an unverified email must neither create an account nor be matched
+	// against one, whoever holds the provider account may not own it
+	if request.Email != "" && !oauthutils.IsEmailVerified(request) {
+		return nil, nil, nil, apperrorAuth.NewOauthEmailNotVerifiedError(request.Provider)
+	}
+
+	recordUserDB, err := u.findOrCreateUser(ctx, request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := checkUserStatus(recordUserDB); err != nil {
+		return nil, nil, nil, err
+	}
+
+	sessionOptions := entity.SessionOptions{
+		LoginMethod: constantAuth.LoginMethodOauthPrefix + request.Provider,
+	}
+
+	isTwoFactorEnabled, err := u.twoFactorUsecase.IsEnabled(ctx, recordUserDB.ID)
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		return nil, nil, nil, err
+	}
+
+	if isTwoFactorEnabled {
+		challenge, err := u.twoFactorUsecase.CreateChallenge(ctx, recordUserDB.ID, sessionOptions)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return recordUserDB, nil, challenge, nil
+	}
+
+	session, err := u.sessionUsecase.Create(ctx, recordUserDB, sessionOptions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return recordUserDB, session, nil, nil
+}
+
+// findOrCreateUser finds the account by the linked identity. The email is
+// only trusted to claim the identity backfilled for a google account created
+// before identities were recorded, and only once google verified it. Any
+// other account must link the provider while signed in.
+func (u *oauthUsecaseImpl) findOrCreateUser(ctx context.Context, request *goth.User) (*entity.User, error) {
+	identity, err := u.userIdentityRepo.FindByProvider(ctx, request.Provider, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != nil {
+		if err := u.userIdentityRepo.UpdateLastUsedAt(ctx, identity.ID); err != nil {
+			return nil, err
+		}
+		return u.userRepo.Find(ctx, "id", identity.UserID)
 	}
-	if recordUserDB != nil && !recordUserDB.IsOauth {
-		return nil, nil, apperrorAuth.NewInvalidEmailAlreadyExists(err)
+
+	// some providers let the user withhold the email, an account cannot be
+	// created or matched without one
+	if request.Email == "" {
+		return nil, apperrorAuth.NewOauthEmailMissingError(request.Provider)
 	}
-	if recordUserDB == nil {
-		recordUserDB = &entity.User{Email: request.Email}
-		if err := u.userRepo.SaveOauth(ctx, recordUserDB); err != nil {
-			return nil, nil, apperrorPkg.NewServerError(err)
+
+	recordUserDB := new(entity.User)
+	err = u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		checkEmail, err := u.userRepo.Find(txCtx, "email", request.Email)
+		if err != nil && err != apperrorPkg.NewNoRowsError(err, request.Email).OriginalError() {
+			return err
+		}
+
+		if checkEmail != nil {
+			unclaimed, err := u.userIdentityRepo.FindUnclaimedByUserID(txCtx, checkEmail.ID)
+			if err != nil {
+				return err
+			}
+			if unclaimed == nil || unclaimed.Provider != request.Provider || !oauthutils.IsEmailVerified(request) {
+				return apperrorAuth.NewOauthAccountNotLinkedError(request.Provider)
+			}
+
+			unclaimed.ProviderUserID = request.UserID
+			unclaimed.Email = request.Email
+			claimed, err := u.userIdentityRepo.Claim(txCtx, unclaimed)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				return apperrorAuth.NewOauthAccountNotLinkedError(request.Provider)
+			}
+
+			recordUserDB = checkEmail
+			return nil
+		}
+
+		recordUserDB.Email = request.Email
+		if err := u.userRepo.SaveOauth(txCtx, recordUserDB); err != nil {
+			return apperrorPkg.NewServerError(err)
 		}
 
 		birthDate, err := time.Parse(constantPkg.DEFAULT_DATE_ONLY, "2000-12-30")
 		if err != nil {
-			return nil, nil, apperrorPkg.NewServerError(err)
+			return apperrorPkg.NewServerError(err)
 		}
 
 		userDetail := new(entity.UserDetail)
@@ -75,38 +172,84 @@ func (u *oauthUsecaseImpl) Login(ctx context.Context, request *goth.User) (*enti
 		userDetail.BirthDate = custom_typePkg.DateOnly(birthDate)
 		userDetail.CreatedBy = recordUserDB.CreatedBy
 
-		if _, err := u.userDetailRepo.Save(ctx, userDetail); err != nil {
-			return nil, nil, apperrorPkg.NewServerError(err)
+		if _, err := u.userDetailRepo.Save(txCtx, userDetail); err != nil {
+			return apperrorPkg.NewServerError(err)
 		}
-	}
 
-	jti := uuid.NewString()
-	accessToken, err := u.jwtUtil.Sign(recordUserDB.ID, recordUserDB.Role, jti, currentTime)
+		now := time.Now()
+		return u.userIdentityRepo.Save(txCtx, &entity.UserIdentity{
+			UserID:         recordUserDB.ID,
+			Provider:       request.Provider,
+			ProviderUserID: request.UserID,
+			Email:          request.Email,
+			LastUsedAt:     &now,
+		})
+	})
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		return nil, err
+	}
+
+	return recordUserDB, nil
+}
+
+// Link finishes a link started from the account settings. It reports false
+// when the state belongs to no link so the callback carries on as a login.
+// The link has to finish in the session that started it, otherwise a link
+// url handed to someone else would attach their provider account.
+func (u *oauthUsecaseImpl) Link(ctx context.Context, state string, userID uuid.UUID, request *goth.User) (bool, error) {
+	if state == "" {
+		return false, nil
 	}
 
-	refreshToken, err := u.jwtUtil.SignRefresh(currentTime)
+	val, err := u.redisUtil.GetDel(ctx, utils.OauthLinkKey(state))
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		return false, apperrorPkg.NewServerError(err)
 	}
 
-	sessionID := uuid.New()
+	if val == "" {
+		return false, nil
+	}
+
+	link := new(entity.OauthLink)
+	if err := json.Unmarshal([]byte(val), link); err != nil {
+		return false, apperrorPkg.NewServerError(err)
+	}
 
-	payloadSession := &entity.Session{
-		UserID:       recordUserDB.ID,
-		Role:         recordUserDB.Role,
-		JTI:          jti,
-		SessionID:    sessionID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		LoginAt:      uint64(currentTime.UnixMilli()),
+	if link.UserID != userID || link.Provider != request.Provider {
+		return false, apperrorAuth.NewInvalidOauthLinkError()
+	}
+
+	identity, err := u.userIdentityRepo.FindByProvider(ctx, request.Provider, request.UserID)
+	if err != nil {
+		return false, err
 	}
 
-	err = u.redisUtil.Set(ctx, utils.SessionKey(sessionID), payloadSession, 24*time.Hour)
+	if identity != nil {
+		if identity.UserID != link.UserID {
+			return false, apperrorAuth.NewIdentityAlreadyLinkedError(request.Provider)
+		}
+		return true, nil
+	}
+
+	err = u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		if err := u.userIdentityRepo.Save(txCtx, &entity.UserIdentity{
+			UserID:         link.UserID,
+			Provider:       request.Provider,
+			ProviderUserID: request.UserID,
+			Email:          request.Email,
+		}); err != nil {
+			// linked to another account since the lookup above
+			if appErr, ok := err.(*apperrorPkg.AppError); ok && database.IsUniqueViolation(appErr.OriginalError()) {
+				return apperrorAuth.NewIdentityAlreadyLinkedError(request.Provider)
+			}
+			return err
+		}
+
+		return u.auditEventUsecase.Record(txCtx, link.UserID, constantAuth.AuditEventIdentityLinked, &request.Provider)
+	})
 	if err != nil {
-		return nil, nil, apperrorPkg.NewServerError(err)
+		return false, err
 	}
 
-	return recordUserDB, payloadSession, nil
+	return true, nil
 }
@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	constantPkg "github.com/faisalyudiansah/auth-service-template/pkg/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type OidcUsecase interface {
+	Authorize(ctx context.Context, req *dto_request.OIDCAuthorize) (string, error)
+	Token(ctx context.Context, req *dto_request.OIDCToken) (*entity.OIDCToken, error)
+	UserInfo(ctx context.Context, userID, sessionID uuid.UUID) (*entity.User, string, error)
+}
+
+type oidcUsecaseImpl struct {
+	userRepo       repository.UserRepository
+	userDetailRepo repository.UserDetailRepository
+	sessionRepo    repository.SessionRepository
+	redisUtil      redisutils.RedisUtil
+	jwtUtil        jwtutils.JwtUtilInterface
+	sessionUsecase SessionUsecase
+	cfg            *config.Config
+}
+
+func NewOidcUsecase(
+	userRepo repository.UserRepository,
+	userDetailRepo repository.UserDetailRepository,
+	sessionRepo repository.SessionRepository,
+	redisUtil redisutils.RedisUtil,
+	jwtUtil jwtutils.JwtUtilInterface,
+	sessionUsecase SessionUsecase,
+	cfg *config.Config,
+) *oidcUsecaseImpl {
+	return &oidcUsecaseImpl{
+		userRepo:       userRepo,
+		userDetailRepo: userDetailRepo,
+		sessionRepo:    sessionRepo,
+		redisUtil:      redisUtil,
+		jwtUtil:        jwtUtil,
+		sessionUsecase: sessionUsecase,
+		cfg:            cfg,
+	}
+}
+
+func (u *oidcUsecaseImpl) Authorize(ctx context.Context, req *dto_request.OIDCAuthorize) (string, error) {
+	client, ok := u.cfg.OIDC.Clients[req.ClientID]
+	if !ok {
+		return "", apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidClient, "unknown client_id")
+	}
+
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return "", apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidRequest, "redirect_uri is not registered for this client")
+	}
+
+	redirect := func(params url.Values) (string, error) {
+		redirectURL, err := url.Parse(req.RedirectURI)
+		if err != nil {
+			return "", apperrorPkg.NewServerError(err)
+		}
+
+		query := redirectURL.Query()
+		for key, values := range params {
+			query[key] = values
+		}
+		if req.State != "" {
+			query.Set("state", req.State)
+		}
+		redirectURL.RawQuery = query.Encode()
+
+		return redirectURL.String(), nil
+	}
+
+	redirectError := func(code, description string) (string, error) {
+		return redirect(url.Values{
+			"error":             {code},
+			"error_description": {description},
+		})
+	}
+
+	if req.ResponseType != constantAuth.OIDC_RESPONSE_TYPE_CODE {
+		return redirectError(constantAuth.OAuthErrorUnsupportedResponseType, "only the code response type is supported")
+	}
+
+	if !slices.Contains(strings.Fields(req.Scope), constantAuth.OIDC_SCOPE_OPENID) {
+		return redirectError(constantAuth.OAuthErrorInvalidScope, "the openid scope is required")
+	}
+
+	// a public client has no secret, PKCE is the only proof that the code is
+	// redeemed by the app that asked for it
+	if req.CodeChallenge == "" && client.Public {
+		return redirectError(constantAuth.OAuthErrorInvalidRequest, "code_challenge is required for public clients")
+	}
+
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256 {
+		return redirectError(constantAuth.OAuthErrorInvalidRequest, "code_challenge_method must be S256")
+	}
+
+	session, err := u.sessionRepo.Find(ctx, req.SessionID)
+	if err != nil {
+		return "", err
+	}
+
+	if session == nil || session.UserID != req.UserID {
+		return "", apperrorPkg.NewForbiddenAccessError()
+	}
+
+	code := uuid.NewString()
+	authorizationCode := &entity.OIDCAuthorizationCode{
+		ClientID:            client.ID,
+		RedirectURI:         req.RedirectURI,
+		UserID:              req.UserID,
+		Scope:               req.Scope,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		AuthTime:            session.LoginAt,
+		ClientIP:            utils.GetClientIPFromContext(ctx),
+		UserAgent:           utils.GetUserAgentFromContext(ctx),
+	}
+
+	if err := u.redisUtil.Set(ctx, utils.OIDCCodeKey(code), authorizationCode, constantAuth.OIDCCodeExpireDuration); err != nil {
+		return "", apperrorPkg.NewServerError(err)
+	}
+
+	return redirect(url.Values{"code": {code}})
+}
+
+func (u *oidcUsecaseImpl) Token(ctx context.Context, req *dto_request.OIDCToken) (*entity.OIDCToken, error) {
+	client, ok := u.cfg.OIDC.Clients[req.ClientID]
+	if !ok || !isClientAuthenticated(client, req.ClientSecret) {
+		return nil, apperrorAuth.NewOAuthInvalidClientError()
+	}
+
+	switch req.GrantType {
+	case constantAuth.OIDC_GRANT_TYPE_AUTHORIZATION:
+		return u.exchangeCode(ctx, client, req)
+	case constantAuth.OIDC_GRANT_TYPE_REFRESH_TOKEN:
+		return u.exchangeRefreshToken(ctx, client, req)
+	}
+
+	return nil, apperrorAuth.NewOAuthError(constantAuth.OAuthErrorUnsupportedGrantType, "unsupported grant_type")
+}
+
+func (u *oidcUsecaseImpl) UserInfo(ctx context.Context, userID, sessionID uuid.UUID) (*entity.User, string, error) {
+	session, err := u.sessionRepo.Find(ctx, sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if session == nil || session.UserID != userID {
+		return nil, "", apperrorPkg.NewForbiddenAccessError()
+	}
+
+	user, err := u.findUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, session.Scope, nil
+}
+
+func (u *oidcUsecaseImpl) exchangeCode(ctx context.Context, client *config.OIDCClient, req *dto_request.OIDCToken) (*entity.OIDCToken, error) {
+	invalidGrant := apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidGrant, "authorization code is invalid or expired")
+
+	val, err := u.redisUtil.GetDel(ctx, utils.OIDCCodeKey(req.Code))
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	if val == "" {
+		return nil, invalidGrant
+	}
+
+	authorizationCode := &entity.OIDCAuthorizationCode{}
+	if err := json.Unmarshal([]byte(val), authorizationCode); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	if authorizationCode.ClientID != client.ID || authorizationCode.RedirectURI != req.RedirectURI {
+		return nil, invalidGrant
+	}
+
+	if client.Public && authorizationCode.CodeChallenge == "" {
+		return nil, invalidGrant
+	}
+
+	if !verifyCodeChallenge(authorizationCode, req.CodeVerifier) {
+		return nil, apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidGrant, "code_verifier does not match the code_challenge")
+	}
+
+	user, err := u.findUser(ctx, authorizationCode.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the code is redeemed by the relying party's server, the session is
+	// stamped with the browser that authorized it instead
+	clientCtx := context.WithValue(ctx, constantPkg.ContextClientIP, authorizationCode.ClientIP)
+	clientCtx = context.WithValue(clientCtx, constantPkg.ContextUserAgent, authorizationCode.UserAgent)
+
+	session, err := u.sessionUsecase.CreateForClient(clientCtx, user, client.ID, authorizationCode.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := u.signIDToken(user, session, authorizationCode.Nonce, int64(authorizationCode.AuthTime/1000))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.OIDCToken{
+		Session: session,
+		IDToken: idToken,
+	}, nil
+}
+
+func (u *oidcUsecaseImpl) exchangeRefreshToken(ctx context.Context, client *config.OIDCClient, req *dto_request.OIDCToken) (*entity.OIDCToken, error) {
+	claims, err := u.jwtUtil.Parse(req.RefreshToken, jwtutils.TokenTypeRefresh)
+	if err != nil || claims.SessionID == uuid.Nil {
+		return nil, apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidGrant, "refresh token is invalid or expired")
+	}
+
+	session, err := u.sessionUsecase.Refresh(ctx, claims.SessionID, req.RefreshToken, client.ID)
+	if err != nil {
+		var appErr *apperrorPkg.AppError
+		if errors.As(err, &appErr) && appErr.GetCode() != apperrorPkg.DefaultServerErrorCode {
+			return nil, apperrorAuth.NewOAuthError(constantAuth.OAuthErrorInvalidGrant, appErr.Error())
+		}
+		return nil, err
+	}
+
+	user, err := u.findUser(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := u.signIDToken(user, session, "", int64(session.LoginAt/1000))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.OIDCToken{
+		Session: session,
+		IDToken: idToken,
+	}, nil
+}
+
+func (u *oidcUsecaseImpl) findUser(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := u.userRepo.Find(ctx, "id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userDetail, err := u.userDetailRepo.Find(ctx, "user_id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.UserDetail = userDetail
+
+	return user, nil
+}
+
+func (u *oidcUsecaseImpl) signIDToken(user *entity.User, session *entity.Session, nonce string, authTime int64) (string, error) {
+	currentTime := time.Now()
+	scopes := strings.Fields(session.Scope)
+
+	claims := &jwtutils.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    u.cfg.OIDC.Issuer,
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{session.ClientID},
+			IssuedAt:  jwt.NewNumericDate(currentTime),
+			ExpiresAt: jwt.NewNumericDate(currentTime.Add(time.Duration(u.cfg.Jwt.TokenDuration) * time.Minute)),
+		},
+		AuthTime:  authTime,
+		Nonce:     nonce,
+		SessionID: session.SessionID.String(),
+	}
+
+	if slices.Contains(scopes, constantAuth.OIDC_SCOPE_EMAIL) {
+		claims.Email = user.Email
+		emailVerified := user.IsVerified()
+		claims.EmailVerified = &emailVerified
+	}
+
+	if slices.Contains(scopes, constantAuth.OIDC_SCOPE_PROFILE) && user.UserDetail != nil {
+		claims.Name = user.UserDetail.FullName
+		claims.Picture = user.UserDetail.ImageURL
+	}
+
+	idToken, err := u.jwtUtil.SignIDToken(claims)
+	if err != nil {
+		return "", apperrorPkg.NewServerError(err)
+	}
+
+	return idToken, nil
+}
+
+// isClientAuthenticated checks the secret of a confidential client, public
+// clients have none and are held to PKCE instead.
+func isClientAuthenticated(client *config.OIDCClient, secret string) bool {
+	if client.Public {
+		return true
+	}
+
+	if client.Secret == "" || secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(client.Secret), []byte(secret)) == 1
+}
+
+func verifyCodeChallenge(code *entity.OIDCAuthorizationCode, verifier string) bool {
+	if code.CodeChallenge == "" {
+		return true
+	}
+
+	if verifier == "" || code.CodeChallengeMethod != constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(code.CodeChallenge)) == 1
+}
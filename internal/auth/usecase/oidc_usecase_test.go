@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testRedirectURI  = "https://app.example.com/callback"
+	testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r7wW1gFWFOEjXk"
+)
+
+func TestOidcUsecase_TokenCodeExchange(t *testing.T) {
+	sum := sha256.Sum256([]byte(testCodeVerifier))
+	codeChallenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	confidential := &config.OIDCClient{ID: "confidential", Secret: "secret", RedirectURIs: []string{testRedirectURI}}
+	public := &config.OIDCClient{ID: "public", Public: true, RedirectURIs: []string{testRedirectURI}}
+
+	tests := []struct {
+		name string
+		code entity.OIDCAuthorizationCode
+		req  dto_request.OIDCToken
+		// wantErr is the oauth error code, empty when the exchange succeeds
+		wantErr string
+	}{
+		{
+			name: "confidential client without pkce",
+			code: entity.OIDCAuthorizationCode{ClientID: confidential.ID, RedirectURI: testRedirectURI},
+			req:  dto_request.OIDCToken{ClientID: confidential.ID, ClientSecret: "secret", RedirectURI: testRedirectURI},
+		},
+		{
+			name:    "confidential client with a wrong secret",
+			code:    entity.OIDCAuthorizationCode{ClientID: confidential.ID, RedirectURI: testRedirectURI},
+			req:     dto_request.OIDCToken{ClientID: confidential.ID, ClientSecret: "wrong", RedirectURI: testRedirectURI},
+			wantErr: constantAuth.OAuthErrorInvalidClient,
+		},
+		{
+			name: "confidential client with pkce",
+			code: entity.OIDCAuthorizationCode{ClientID: confidential.ID, RedirectURI: testRedirectURI, CodeChallenge: codeChallenge, CodeChallengeMethod: constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+			req:  dto_request.OIDCToken{ClientID: confidential.ID, ClientSecret: "secret", RedirectURI: testRedirectURI, CodeVerifier: testCodeVerifier},
+		},
+		{
+			name: "public client with the right verifier",
+			code: entity.OIDCAuthorizationCode{ClientID: public.ID, RedirectURI: testRedirectURI, CodeChallenge: codeChallenge, CodeChallengeMethod: constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+			req:  dto_request.OIDCToken{ClientID: public.ID, RedirectURI: testRedirectURI, CodeVerifier: testCodeVerifier},
+		},
+		{
+			name:    "public client with a wrong verifier",
+			code:    entity.OIDCAuthorizationCode{ClientID: public.ID, RedirectURI: testRedirectURI, CodeChallenge: codeChallenge, CodeChallengeMethod: constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+			req:     dto_request.OIDCToken{ClientID: public.ID, RedirectURI: testRedirectURI, CodeVerifier: "wrong-verifier"},
+			wantErr: constantAuth.OAuthErrorInvalidGrant,
+		},
+		{
+			name:    "public client without a verifier",
+			code:    entity.OIDCAuthorizationCode{ClientID: public.ID, RedirectURI: testRedirectURI, CodeChallenge: codeChallenge, CodeChallengeMethod: constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+			req:     dto_request.OIDCToken{ClientID: public.ID, RedirectURI: testRedirectURI},
+			wantErr: constantAuth.OAuthErrorInvalidGrant,
+		},
+		{
+			name:    "public client code without a challenge",
+			code:    entity.OIDCAuthorizationCode{ClientID: public.ID, RedirectURI: testRedirectURI},
+			req:     dto_request.OIDCToken{ClientID: public.ID, RedirectURI: testRedirectURI, CodeVerifier: testCodeVerifier},
+			wantErr: constantAuth.OAuthErrorInvalidGrant,
+		},
+		{
+			name:    "plain challenge method",
+			code:    entity.OIDCAuthorizationCode{ClientID: public.ID, RedirectURI: testRedirectURI, CodeChallenge: testCodeVerifier, CodeChallengeMethod: "plain"},
+			req:     dto_request.OIDCToken{ClientID: public.ID, RedirectURI: testRedirectURI, CodeVerifier: testCodeVerifier},
+			wantErr: constantAuth.OAuthErrorInvalidGrant,
+		},
+		{
+			name:    "redirect uri mismatch",
+			code:    entity.OIDCAuthorizationCode{ClientID: confidential.ID, RedirectURI: testRedirectURI},
+			req:     dto_request.OIDCToken{ClientID: confidential.ID, ClientSecret: "secret", RedirectURI: "https://evil.example.com/callback"},
+			wantErr: constantAuth.OAuthErrorInvalidGrant,
+		},
+		{
+			name:    "code issued to another client",
+			code:    entity.OIDCAuthorizationCode{ClientID: public.ID, RedirectURI: testRedirectURI, CodeChallenge: codeChallenge, CodeChallengeMethod: constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+			req:     dto_request.OIDCToken{ClientID: confidential.ID, ClientSecret: "secret", RedirectURI: testRedirectURI, CodeVerifier: testCodeVerifier},
+			wantErr: constantAuth.OAuthErrorInvalidGrant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &entity.User{ID: uuid.New(), Status: custom_typeAuth.UserStatusActive}
+
+			cfg := newTestConfig()
+			cfg.OIDC.Clients[confidential.ID] = confidential
+			cfg.OIDC.Clients[public.ID] = public
+
+			sessionUsecase, redisUtil := newTestSessionUsecase(cfg, user)
+			usecase := NewOidcUsecase(
+				newFakeUserRepository(user),
+				&fakeUserDetailRepository{},
+				sessionUsecase.sessionRepo,
+				redisUtil,
+				sessionUsecase.jwtUtil,
+				sessionUsecase,
+				cfg,
+			)
+
+			code := uuid.NewString()
+			authorizationCode := tt.code
+			authorizationCode.UserID = user.ID
+			authorizationCode.Scope = "openid email"
+			if err := redisUtil.Set(ctx, utils.OIDCCodeKey(code), &authorizationCode, constantAuth.OIDCCodeExpireDuration); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			req := tt.req
+			req.GrantType = constantAuth.OIDC_GRANT_TYPE_AUTHORIZATION
+			req.Code = code
+
+			token, err := usecase.Token(ctx, &req)
+			if tt.wantErr != "" {
+				var oauthErr *apperrorAuth.OAuthError
+				if !errors.As(err, &oauthErr) || oauthErr.Code != tt.wantErr {
+					t.Fatalf("Token() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if token.IDToken == "" || token.Session.ClientID != req.ClientID || token.Session.UserID != user.ID {
+				t.Fatalf("Token() = %+v, want a session of %s for the user", token, req.ClientID)
+			}
+
+			// a code is redeemed once
+			_, err = usecase.Token(ctx, &req)
+			var oauthErr *apperrorAuth.OAuthError
+			if !errors.As(err, &oauthErr) || oauthErr.Code != constantAuth.OAuthErrorInvalidGrant {
+				t.Fatalf("second Token() error = %v, want %s", err, constantAuth.OAuthErrorInvalidGrant)
+			}
+		})
+	}
+}
+
+func TestOidcUsecase_AuthorizePKCE(t *testing.T) {
+	sum := sha256.Sum256([]byte(testCodeVerifier))
+	codeChallenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	public := &config.OIDCClient{ID: "public", Public: true, RedirectURIs: []string{testRedirectURI}}
+
+	tests := []struct {
+		name                string
+		codeChallenge       string
+		codeChallengeMethod string
+		// wantErr is the error the client is redirected with, empty when it
+		// is redirected with a code
+		wantErr string
+	}{
+		{name: "s256 challenge", codeChallenge: codeChallenge, codeChallengeMethod: constantAuth.OIDC_CODE_CHALLENGE_METHOD_S256},
+		{name: "no challenge", wantErr: constantAuth.OAuthErrorInvalidRequest},
+		{name: "plain challenge", codeChallenge: testCodeVerifier, codeChallengeMethod: "plain", wantErr: constantAuth.OAuthErrorInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &entity.User{ID: uuid.New(), Status: custom_typeAuth.UserStatusActive}
+
+			cfg := newTestConfig()
+			cfg.OIDC.Clients[public.ID] = public
+
+			sessionUsecase, redisUtil := newTestSessionUsecase(cfg, user)
+			usecase := NewOidcUsecase(
+				newFakeUserRepository(user),
+				&fakeUserDetailRepository{},
+				sessionUsecase.sessionRepo,
+				redisUtil,
+				sessionUsecase.jwtUtil,
+				sessionUsecase,
+				cfg,
+			)
+
+			session, err := sessionUsecase.Create(ctx, user, entity.SessionOptions{})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			redirectURL, err := usecase.Authorize(ctx, &dto_request.OIDCAuthorize{
+				ResponseType:        constantAuth.OIDC_RESPONSE_TYPE_CODE,
+				ClientID:            public.ID,
+				RedirectURI:         testRedirectURI,
+				Scope:               constantAuth.OIDC_SCOPE_OPENID,
+				State:               "state",
+				CodeChallenge:       tt.codeChallenge,
+				CodeChallengeMethod: tt.codeChallengeMethod,
+				UserID:              user.ID,
+				SessionID:           session.SessionID,
+			})
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+
+			parsed, err := url.Parse(redirectURL)
+			if err != nil {
+				t.Fatalf("Authorize() redirect %q: %v", redirectURL, err)
+			}
+			query := parsed.Query()
+
+			if query.Get("state") != "state" {
+				t.Fatalf("redirect %q does not carry the state", redirectURL)
+			}
+
+			if tt.wantErr != "" {
+				if query.Get("error") != tt.wantErr || query.Get("code") != "" {
+					t.Fatalf("redirect %q, want error %s", redirectURL, tt.wantErr)
+				}
+				return
+			}
+
+			token, err := usecase.Token(ctx, &dto_request.OIDCToken{
+				GrantType:    constantAuth.OIDC_GRANT_TYPE_AUTHORIZATION,
+				Code:         query.Get("code"),
+				RedirectURI:  testRedirectURI,
+				CodeVerifier: testCodeVerifier,
+				ClientID:     public.ID,
+			})
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if token.Session.SessionID == session.SessionID {
+				t.Fatalf("relying party must get a session of its own")
+			}
+		})
+	}
+}
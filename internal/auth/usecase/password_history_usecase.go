@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
+)
+
+type PasswordHistoryUsecase interface {
+	CheckReuse(ctx context.Context, user *entity.User, password string) error
+	Record(ctx context.Context, user *entity.User) error
+}
+
+type passwordHistoryUsecaseImpl struct {
+	passwordHistoryRepo repository.PasswordHistoryRepository
+	passwordEncryptor   encryptutils.PasswordEncryptor
+	cfg                 *config.Config
+}
+
+func NewPasswordHistoryUsecase(
+	passwordHistoryRepo repository.PasswordHistoryRepository,
+	passwordEncryptor encryptutils.PasswordEncryptor,
+	cfg *config.Config,
+) *passwordHistoryUsecaseImpl {
+	return &passwordHistoryUsecaseImpl{
+		passwordHistoryRepo: passwordHistoryRepo,
+		passwordEncryptor:   passwordEncryptor,
+		cfg:                 cfg,
+	}
+}
+
+// CheckReuse rejects the current password as well as any of the last
+// PASSWORD_HISTORY_SIZE ones, accounts created before the history existed
+// are only checked against their current hash.
+func (u *passwordHistoryUsecaseImpl) CheckReuse(ctx context.Context, user *entity.User, password string) error {
+	if user.HashPassword != "" && u.passwordEncryptor.Check(password, user.HashPassword) {
+		return apperrorAuth.NewSamePasswordError()
+	}
+
+	historySize := u.cfg.PasswordPolicy.HistorySize
+	if historySize <= 0 {
+		return nil
+	}
+
+	histories, err := u.passwordHistoryRepo.FindLatestByUserID(ctx, user.ID, historySize)
+	if err != nil {
+		return err
+	}
+
+	for _, history := range histories {
+		if u.passwordEncryptor.Check(password, history.HashPassword) {
+			return apperrorAuth.NewPasswordReusedError(historySize)
+		}
+	}
+
+	return nil
+}
+
+// Record stores the hash the user has just been given and clears the ones
+// that fell out of the history. Every change keeps its row, so a data export
+// lists all of them, without a history only the time of the change is kept.
+func (u *passwordHistoryUsecaseImpl) Record(ctx context.Context, user *entity.User) error {
+	if user.HashPassword == "" {
+		return nil
+	}
+
+	historySize := u.cfg.PasswordPolicy.HistorySize
+
+	history := &entity.PasswordHistory{
+		UserID: user.ID,
+	}
+	if historySize > 0 {
+		history.HashPassword = user.HashPassword
+	}
+
+	if err := u.passwordHistoryRepo.Save(ctx, history); err != nil {
+		return err
+	}
+
+	if historySize <= 0 {
+		return nil
+	}
+
+	return u.passwordHistoryRepo.ClearExceptLatest(ctx, user.ID, historySize)
+}
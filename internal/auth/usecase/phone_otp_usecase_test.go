@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+const testPhoneOTPCode = "123456"
+
+func TestPhoneOTPUsecase_VerifyAttemptCap(t *testing.T) {
+	tests := []struct {
+		name      string
+		codes     []string
+		wantValid bool
+		// wantStored tells whether the code can still be tried afterwards
+		wantStored bool
+	}{
+		{
+			name:      "right code first",
+			codes:     []string{testPhoneOTPCode},
+			wantValid: true,
+		},
+		{
+			name:      "right code on the last attempt",
+			codes:     append(repeat("000000", constantAuth.PhoneOTPMaxAttempts-1), testPhoneOTPCode),
+			wantValid: true,
+		},
+		{
+			name:       "wrong code below the cap",
+			codes:      repeat("000000", constantAuth.PhoneOTPMaxAttempts-1),
+			wantStored: true,
+		},
+		{
+			name:  "wrong code on the last attempt",
+			codes: repeat("000000", constantAuth.PhoneOTPMaxAttempts),
+		},
+		{
+			name:  "right code after the cap",
+			codes: append(repeat("000000", constantAuth.PhoneOTPMaxAttempts), testPhoneOTPCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			subject := "+6281234567890"
+			redisUtil := newFakeRedisUtil()
+			usecase := NewPhoneOTPUsecase(redisUtil, nil, &config.Config{SMS: &config.SMSConfig{}})
+
+			otpKey := utils.PhoneOTPKey(constantAuth.PhoneOTPPurposeVerify, subject)
+			if err := redisUtil.SetJSON(ctx, otpKey, &entity.PhoneOTP{
+				UserID:    uuid.New(),
+				CodeHash:  hashPhoneOTPCode(testPhoneOTPCode),
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(constantAuth.PhoneOTPExpireDuration),
+			}, constantAuth.PhoneOTPExpireDuration); err != nil {
+				t.Fatalf("SetJSON() error = %v", err)
+			}
+
+			var (
+				otp *entity.PhoneOTP
+				err error
+			)
+			for _, code := range tt.codes {
+				otp, err = usecase.Verify(ctx, constantAuth.PhoneOTPPurposeVerify, subject, code)
+			}
+
+			if tt.wantValid {
+				if err != nil || otp == nil {
+					t.Fatalf("Verify() = %v, %v, want the otp", otp, err)
+				}
+			} else if err == nil || err.Error() != constantAuth.InvalidPhoneOTPErrorMessage {
+				t.Fatalf("Verify() error = %v, want %q", err, constantAuth.InvalidPhoneOTPErrorMessage)
+			}
+
+			stored, _ := redisUtil.Get(ctx, otpKey)
+			if (stored != "") != tt.wantStored {
+				t.Fatalf("otp stored = %v, want %v", stored != "", tt.wantStored)
+			}
+		})
+	}
+}
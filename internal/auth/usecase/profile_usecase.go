@@ -2,14 +2,21 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
 	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/tasks"
 	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
 	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
 	dtoPkg "github.com/faisalyudiansah/auth-service-template/pkg/dto"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/breachutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
 
@@ -22,15 +29,26 @@ type ProfileUsecase interface {
 	GetMe(ctx context.Context, userID uuid.UUID) (*entity.User, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*entity.User, error)
 	UpdateUser(ctx context.Context, req *dto_request.UpdateUser) (*entity.User, error)
+	ChangePassword(ctx context.Context, req *dto_request.ChangePassword) error
+	SendPhoneVerification(ctx context.Context, userID uuid.UUID) error
+	VerifyPhoneNumber(ctx context.Context, req *dto_request.VerifyPhoneNumber) error
+	RequestAccountDeletion(ctx context.Context, req *dto_request.RequestAccountDeletion) error
 	DeleteUser(ctx context.Context, req *dto_request.DeleteUser) error
 }
 
 type profileUsecaseImpl struct {
-	userRepo          repository.UserRepository
-	userDetailRepo    repository.UserDetailRepository
-	redisUtil         redisutils.RedisUtil
-	passwordEncryptor encryptutils.PasswordEncryptor
-	transactor        transactor.Transactor
+	userRepo               repository.UserRepository
+	userDetailRepo         repository.UserDetailRepository
+	redisUtil              redisutils.RedisUtil
+	passwordEncryptor      encryptutils.PasswordEncryptor
+	breachChecker          breachutils.BreachChecker
+	sessionUsecase         SessionUsecase
+	passwordHistoryUsecase PasswordHistoryUsecase
+	phoneOTPUsecase        PhoneOTPUsecase
+	accountStatusUsecase   AccountStatusUsecase
+	accountDeletionUsecase AccountDeletionUsecase
+	emailTask              tasks.EmailTask
+	transactor             transactor.Transactor
 }
 
 func NewProfileUsecase(
@@ -38,14 +56,28 @@ func NewProfileUsecase(
 	userDetailRepo repository.UserDetailRepository,
 	redisUtil redisutils.RedisUtil,
 	passwordEncryptor encryptutils.PasswordEncryptor,
+	breachChecker breachutils.BreachChecker,
+	sessionUsecase SessionUsecase,
+	passwordHistoryUsecase PasswordHistoryUsecase,
+	phoneOTPUsecase PhoneOTPUsecase,
+	accountStatusUsecase AccountStatusUsecase,
+	accountDeletionUsecase AccountDeletionUsecase,
+	emailTask tasks.EmailTask,
 	transactor transactor.Transactor,
 ) *profileUsecaseImpl {
 	return &profileUsecaseImpl{
-		userRepo:          userRepo,
-		userDetailRepo:    userDetailRepo,
-		redisUtil:         redisUtil,
-		passwordEncryptor: passwordEncryptor,
-		transactor:        transactor,
+		userRepo:               userRepo,
+		userDetailRepo:         userDetailRepo,
+		redisUtil:              redisUtil,
+		passwordEncryptor:      passwordEncryptor,
+		breachChecker:          breachChecker,
+		sessionUsecase:         sessionUsecase,
+		passwordHistoryUsecase: passwordHistoryUsecase,
+		phoneOTPUsecase:        phoneOTPUsecase,
+		accountStatusUsecase:   accountStatusUsecase,
+		accountDeletionUsecase: accountDeletionUsecase,
+		emailTask:              emailTask,
+		transactor:             transactor,
 	}
 }
 
@@ -157,18 +189,43 @@ func (u *profileUsecaseImpl) UpdateUser(ctx context.Context, req *dto_request.Up
 		}
 
 		if req.RoleWhoIsEdit.IsRoleAdmin() {
+			isRoleChanged := recordUserDB.Role != *req.Role
+			// suspending a suspended account again moves its reason and end
+			isStatusChanged := req.Status != nil && (*req.Status != recordUserDB.Status || req.Status.IsUserStatusSuspended())
+
 			recordUserDB.Role = *req.Role
-			recordUserDB.IsVerified = req.IsVerified
-			recordUserDB.IsActive = req.IsActive
 			recordUserDB.UpdatedBy = &req.UpdatedBy
 
 			if err := u.userRepo.Update(cForTx, recordUserDB); err != nil {
 				return err
 			}
+
+			if isStatusChanged {
+				if err := u.accountStatusUsecase.Transition(cForTx, recordUserDB, &entity.StatusChange{
+					To:             *req.Status,
+					ActorID:        req.UpdatedBy,
+					Reason:         req.StatusReason,
+					SuspendedUntil: req.SuspendedUntil,
+				}); err != nil {
+					return err
+				}
+			}
+
+			if isRoleChanged && recordUserDB.Status.IsUserStatusActive() {
+				if err := transactor.AfterCommit(cForTx, func(ctx context.Context) error {
+					return u.sessionUsecase.RefreshAll(ctx, recordUserDB)
+				}); err != nil {
+					return err
+				}
+			}
 		}
 
 		recordUserDetailDB.FullName = req.FullName
 		recordUserDetailDB.Sex = *req.Sex
+		if recordUserDetailDB.PhoneNumber == nil || *recordUserDetailDB.PhoneNumber != req.PhoneNumber {
+			// a new number has to be verified again
+			recordUserDetailDB.PhoneVerifiedAt = nil
+		}
 		recordUserDetailDB.PhoneNumber = &req.PhoneNumber
 		recordUserDetailDB.ImageURL = req.ImageURL
 		recordUserDetailDB.UpdatedBy = &req.UpdatedBy
@@ -190,6 +247,146 @@ func (u *profileUsecaseImpl) UpdateUser(ctx context.Context, req *dto_request.Up
 	return res, nil
 }
 
+func (u *profileUsecaseImpl) ChangePassword(ctx context.Context, req *dto_request.ChangePassword) error {
+	recordUserDB, err := u.userRepo.Find(ctx, "id", req.UserID)
+	if err != nil {
+		return err
+	}
+
+	// oauth-only accounts have no password yet, a fresh login stands in for
+	// the current password so a stolen session cannot set one
+	if recordUserDB.HashPassword == "" {
+		if err := u.checkRecentLogin(ctx, req.UserID, req.SessionID); err != nil {
+			return err
+		}
+	} else if !u.passwordEncryptor.Check(req.CurrentPassword, recordUserDB.HashPassword) {
+		return apperrorAuth.NewInvalidCurrentPasswordError()
+	}
+
+	if u.breachChecker.IsBreached(req.NewPassword) {
+		return apperrorAuth.NewBreachedPasswordError()
+	}
+
+	err = u.transactor.Atomic(ctx, func(cForTx context.Context) error {
+		if err := u.passwordHistoryUsecase.CheckReuse(cForTx, recordUserDB, req.NewPassword); err != nil {
+			return err
+		}
+
+		hashPassword, err := u.passwordEncryptor.Hash(req.NewPassword)
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		recordUserDB.HashPassword = hashPassword
+		recordUserDB.UpdatedBy = &req.UserID
+		if err := u.userRepo.UpdatePassword(cForTx, recordUserDB); err != nil {
+			return err
+		}
+
+		return u.passwordHistoryUsecase.Record(cForTx, recordUserDB)
+	})
+	if err != nil {
+		return err
+	}
+
+	if req.RevokeOtherSessions {
+		if err := u.sessionUsecase.RevokeOthers(ctx, recordUserDB.ID, req.SessionID); err != nil {
+			return err
+		}
+	}
+
+	// the password is already changed, a lost notice must not report failure
+	if err := u.emailTask.QueuePasswordChangedEmail(ctx, &payload.PasswordChangedEmailPayload{
+		Email: recordUserDB.Email,
+	}); err != nil {
+		logger.FromContext(ctx).Errorf("error queueing password changed email of user %v: %v", recordUserDB.ID, err)
+	}
+
+	return nil
+}
+
+func (u *profileUsecaseImpl) checkRecentLogin(ctx context.Context, userID, sessionID uuid.UUID) error {
+	sessions, err := u.sessionUsecase.GetListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.SessionID != sessionID {
+			continue
+		}
+
+		if time.Since(time.UnixMilli(int64(session.LoginAt))) <= constantAuth.RecentLoginDuration {
+			return nil
+		}
+		break
+	}
+
+	return apperrorAuth.NewRecentLoginRequiredError()
+}
+
+func (u *profileUsecaseImpl) SendPhoneVerification(ctx context.Context, userID uuid.UUID) error {
+	userDetailDB, err := u.userDetailRepo.Find(ctx, "user_id", userID)
+	if err != nil {
+		return err
+	}
+
+	if userDetailDB.PhoneNumber == nil {
+		return apperrorAuth.NewPhoneNumberNotSetError()
+	}
+
+	if userDetailDB.PhoneVerifiedAt != nil {
+		return apperrorAuth.NewPhoneNumberVerifiedError()
+	}
+
+	return u.phoneOTPUsecase.Issue(ctx, constantAuth.PhoneOTPPurposeVerify, userID.String(), &entity.PhoneOTP{
+		UserID:      userID,
+		PhoneNumber: *userDetailDB.PhoneNumber,
+	})
+}
+
+func (u *profileUsecaseImpl) VerifyPhoneNumber(ctx context.Context, req *dto_request.VerifyPhoneNumber) error {
+	otp, err := u.phoneOTPUsecase.Verify(ctx, constantAuth.PhoneOTPPurposeVerify, req.UserID.String(), req.Code)
+	if err != nil {
+		return err
+	}
+
+	userDetailDB, err := u.userDetailRepo.Find(ctx, "user_id", req.UserID)
+	if err != nil {
+		return err
+	}
+
+	// the number may have been edited after the code was sent
+	if userDetailDB.PhoneNumber == nil || *userDetailDB.PhoneNumber != otp.PhoneNumber {
+		return apperrorAuth.NewInvalidPhoneOTPError()
+	}
+
+	now := time.Now()
+	userDetailDB.PhoneVerifiedAt = &now
+	userDetailDB.UpdatedBy = &req.UserID
+
+	return u.userDetailRepo.Update(ctx, userDetailDB)
+}
+
+// RequestAccountDeletion asks for the password again, oauth-only accounts have
+// to set one first.
+func (u *profileUsecaseImpl) RequestAccountDeletion(ctx context.Context, req *dto_request.RequestAccountDeletion) error {
+	recordUserDB, err := u.userRepo.Find(ctx, "id", req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if recordUserDB.HashPassword == "" {
+		return apperrorAuth.NewPasswordRequiredError()
+	}
+
+	if !u.passwordEncryptor.Check(req.Password, recordUserDB.HashPassword) {
+		return apperrorAuth.NewInvalidCurrentPasswordError()
+	}
+
+	return u.accountDeletionUsecase.Schedule(ctx, recordUserDB)
+}
+
 func (u *profileUsecaseImpl) DeleteUser(ctx context.Context, req *dto_request.DeleteUser) error {
 	err := u.transactor.Atomic(ctx, func(cForTx context.Context) error {
 		recordUserDB, err := u.userRepo.Find(cForTx, "id", req.UserID)
@@ -206,6 +403,15 @@ func (u *profileUsecaseImpl) DeleteUser(ctx context.Context, req *dto_request.De
 			return apperrorPkg.NewDontHavePermissionErrorMessageError()
 		}
 
+		// the status moves first, the update below hides the row from it
+		if err := u.accountStatusUsecase.Transition(cForTx, recordUserDB, &entity.StatusChange{
+			To:      custom_typeAuth.UserStatusDeleted,
+			ActorID: req.DeletedBy,
+			Reason:  &req.DeletedReason,
+		}); err != nil {
+			return err
+		}
+
 		recordUserDB.DeletedBy = &req.DeletedBy
 		recordUserDB.DeletedReason = &req.DeletedReason
 
@@ -216,11 +422,7 @@ func (u *profileUsecaseImpl) DeleteUser(ctx context.Context, req *dto_request.De
 			return err
 		}
 
-		if err := u.userDetailRepo.Update(cForTx, recordUserDetailDB); err != nil {
-			return err
-		}
-
-		return nil
+		return u.userDetailRepo.Update(cForTx, recordUserDetailDB)
 	})
 
 	return err
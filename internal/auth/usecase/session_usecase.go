@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+	"github.com/faisalyudiansah/auth-service-template/pkg/metrics"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/useragentutils"
+
+	"github.com/google/uuid"
+)
+
+type SessionUsecase interface {
+	Create(ctx context.Context, user *entity.User, opts entity.SessionOptions) (*entity.Session, error)
+	CreateForClient(ctx context.Context, user *entity.User, clientID, scope string) (*entity.Session, error)
+	Refresh(ctx context.Context, sessionID uuid.UUID, refreshToken, clientID string) (*entity.Session, error)
+	GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error)
+	Revoke(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeAll(ctx context.Context, userID uuid.UUID) error
+	RevokeOthers(ctx context.Context, userID, keepSessionID uuid.UUID) error
+	RefreshAll(ctx context.Context, user *entity.User) error
+}
+
+type sessionUsecaseImpl struct {
+	userRepo          repository.UserRepository
+	sessionRepo       repository.SessionRepository
+	auditEventUsecase AuditEventUsecase
+	jwtUtil           jwtutils.JwtUtilInterface
+	cfg               *config.Config
+}
+
+func NewSessionUsecase(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	auditEventUsecase AuditEventUsecase,
+	jwtUtil jwtutils.JwtUtilInterface,
+	cfg *config.Config,
+) *sessionUsecaseImpl {
+	return &sessionUsecaseImpl{
+		userRepo:          userRepo,
+		sessionRepo:       sessionRepo,
+		auditEventUsecase: auditEventUsecase,
+		jwtUtil:           jwtUtil,
+		cfg:               cfg,
+	}
+}
+
+func (u *sessionUsecaseImpl) Create(ctx context.Context, user *entity.User, opts entity.SessionOptions) (*entity.Session, error) {
+	return u.create(ctx, user, "", "", opts)
+}
+
+func (u *sessionUsecaseImpl) CreateForClient(ctx context.Context, user *entity.User, clientID, scope string) (*entity.Session, error) {
+	return u.create(ctx, user, clientID, scope, entity.SessionOptions{
+		LoginMethod: constantAuth.LoginMethodOIDC,
+	})
+}
+
+func (u *sessionUsecaseImpl) create(ctx context.Context, user *entity.User, clientID, scope string, opts entity.SessionOptions) (*entity.Session, error) {
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	currentTime := time.Now()
+
+	session := &entity.Session{
+		UserID:        user.ID,
+		Role:          user.Role,
+		JTI:           uuid.NewString(),
+		SessionID:     uuid.New(),
+		RefreshJTI:    uuid.NewString(),
+		ClientID:      clientID,
+		Scope:         scope,
+		LoginAt:       uint64(currentTime.UnixMilli()),
+		LastRefreshAt: uint64(currentTime.UnixMilli()),
+		RememberMe:    opts.RememberMe,
+		LoginMethod:   opts.LoginMethod,
+	}
+
+	accessToken, err := u.signAccessToken(session, currentTime)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	refreshToken, err := u.jwtUtil.SignRefresh(session.SessionID, session.RefreshJTI, currentTime)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	session.AccessToken = accessToken
+	session.RefreshToken = refreshToken
+	u.recordClient(ctx, session, currentTime)
+
+	if err := u.auditEventUsecase.Record(ctx, user.ID, constantAuth.AuditEventLogin, &session.LoginMethod); err != nil {
+		return nil, err
+	}
+
+	if err := u.save(ctx, session, currentTime); err != nil {
+		return nil, err
+	}
+
+	u.logSession(ctx, session, "session created")
+
+	return session, nil
+}
+
+// Refresh rotates the tokens of a session. clientID is the relying party the
+// session was issued to, empty for first-party sessions, so neither side can
+// refresh the other's sessions.
+func (u *sessionUsecaseImpl) Refresh(ctx context.Context, sessionID uuid.UUID, refreshToken, clientID string) (*entity.Session, error) {
+	currentTime := time.Now()
+
+	session, err := u.sessionRepo.Find(ctx, sessionID)
+	if err != nil || session == nil || session.ClientID != clientID {
+		return nil, apperrorPkg.NewForbiddenAccessError()
+	}
+
+	if session.RefreshToken == "" {
+		return nil, apperrorPkg.NewForbiddenAccessError()
+	}
+
+	if refreshToken != "" {
+		claims, err := u.jwtUtil.Parse(refreshToken, jwtutils.TokenTypeRefresh)
+		if err != nil || claims.SessionID != session.SessionID {
+			return nil, apperrorPkg.NewForbiddenAccessError()
+		}
+
+		if claims.ID != session.RefreshJTI {
+			if u.isJustRotated(session, claims.ID, currentTime) {
+				return nil, apperrorAuth.NewRefreshTokenRotatedError()
+			}
+			return nil, u.revokeReusedFamily(ctx, session, claims.ID)
+		}
+	}
+
+	if u.sessionTTL(session, currentTime) <= 0 {
+		_ = u.sessionRepo.Delete(ctx, session)
+		return nil, apperrorPkg.NewSessionExpiredError()
+	}
+
+	if _, err := u.jwtUtil.Parse(session.RefreshToken, jwtutils.TokenTypeRefresh); err != nil {
+		_ = u.sessionRepo.Delete(ctx, session)
+		return nil, apperrorPkg.NewSessionExpiredError()
+	}
+
+	user, err := u.userRepo.Find(ctx, "id", session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, apperrorPkg.NewForbiddenAccessError()
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		_ = u.sessionRepo.Delete(ctx, session)
+		return nil, err
+	}
+
+	previousRefreshJTI := session.RefreshJTI
+
+	session.Role = user.Role
+	session.JTI = uuid.NewString()
+	session.RefreshJTI = uuid.NewString()
+	session.PreviousRefreshJTI = previousRefreshJTI
+
+	newAccessToken, err := u.signAccessToken(session, currentTime)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	newRefreshToken, err := u.jwtUtil.SignRefresh(sessionID, session.RefreshJTI, currentTime)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	session.AccessToken = newAccessToken
+	session.RefreshToken = newRefreshToken
+	session.LastRefreshAt = uint64(currentTime.UnixMilli())
+
+	// a relying party refreshes from its own server, which says nothing about
+	// the device the user signed in from
+	if session.ClientID == "" {
+		u.recordClient(ctx, session, currentTime)
+	}
+
+	// a concurrent refresh of the same token may have rotated the session
+	// since it was read, only one of them gets to issue new tokens
+	ttl := u.sessionTTL(session, currentTime)
+	rotated, err := u.sessionRepo.Rotate(ctx, session, previousRefreshJTI, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rotated {
+		return nil, apperrorAuth.NewRefreshTokenRotatedError()
+	}
+
+	session.ExpiresAt = currentTime.Add(ttl)
+
+	u.logSession(ctx, session, "session refreshed")
+
+	return session, nil
+}
+
+func (u *sessionUsecaseImpl) GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error) {
+	return u.sessionRepo.FindByUserID(ctx, userID)
+}
+
+func (u *sessionUsecaseImpl) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := u.sessionRepo.Find(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || (userID != uuid.Nil && session.UserID != userID) {
+		return apperrorAuth.NewSessionNotFoundError()
+	}
+
+	return u.sessionRepo.Delete(ctx, session)
+}
+
+func (u *sessionUsecaseImpl) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	return u.sessionRepo.DeleteByUserID(ctx, userID)
+}
+
+func (u *sessionUsecaseImpl) RevokeOthers(ctx context.Context, userID, keepSessionID uuid.UUID) error {
+	sessions, err := u.sessionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.SessionID == keepSessionID {
+			continue
+		}
+
+		if err := u.sessionRepo.Delete(ctx, session); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *sessionUsecaseImpl) RefreshAll(ctx context.Context, user *entity.User) error {
+	currentTime := time.Now()
+
+	sessions, err := u.sessionRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := u.refreshRole(ctx, session, user, currentTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refreshRole reissues the access token of a session with the current role.
+// The session is written back only while it still holds the refresh token it
+// was read with, a refresh or logout in between must not be undone, so it is
+// read again until the write goes through or the session is gone.
+func (u *sessionUsecaseImpl) refreshRole(ctx context.Context, session *entity.Session, user *entity.User, currentTime time.Time) error {
+	for attempt := 0; attempt < constantAuth.SessionRefreshAllAttempts; attempt++ {
+		if attempt > 0 {
+			current, err := u.sessionRepo.Find(ctx, session.SessionID)
+			if err != nil {
+				return err
+			}
+
+			if current == nil || current.UserID != user.ID {
+				return nil
+			}
+			session = current
+		}
+
+		ttl := u.sessionTTL(session, currentTime)
+		if ttl <= 0 {
+			return u.sessionRepo.Delete(ctx, session)
+		}
+
+		session.Role = user.Role
+		session.JTI = uuid.NewString()
+
+		newAccessToken, err := u.signAccessToken(session, currentTime)
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		session.AccessToken = newAccessToken
+
+		rotated, err := u.sessionRepo.Rotate(ctx, session, session.RefreshJTI, ttl)
+		if err != nil {
+			return err
+		}
+
+		if rotated {
+			return nil
+		}
+	}
+
+	return apperrorPkg.NewServerError(fmt.Errorf("session %s kept changing while its role was refreshed", session.SessionID))
+}
+
+func (u *sessionUsecaseImpl) revokeReusedFamily(ctx context.Context, session *entity.Session, presentedJTI string) error {
+	metrics.RefreshTokenReuse.Inc()
+	logger.FromContext(ctx).WithFields(map[string]any{
+		"user_id":       session.UserID.String(),
+		"session_id":    session.SessionID.String(),
+		"presented_jti": presentedJTI,
+	}).Warn("refresh token reuse detected, revoking session family")
+
+	if err := u.sessionRepo.Delete(ctx, session); err != nil {
+		return err
+	}
+
+	return apperrorAuth.NewRefreshTokenReusedError()
+}
+
+// signAccessToken gives relying parties a token of their own type, bound to
+// their client id and granted scope, which first-party endpoints refuse.
+func (u *sessionUsecaseImpl) signAccessToken(session *entity.Session, currentTime time.Time) (string, error) {
+	if session.ClientID != "" {
+		return u.jwtUtil.SignClientAccess(session.UserID, session.SessionID, session.JTI, session.ClientID, session.Scope, currentTime)
+	}
+
+	return u.jwtUtil.Sign(session.UserID, session.Role, session.SessionID, session.JTI, currentTime)
+}
+
+// isJustRotated tells a refresh that lost the race against a concurrent one
+// apart from a replayed token: the token it presents was rotated out moments
+// ago.
+func (u *sessionUsecaseImpl) isJustRotated(session *entity.Session, presentedJTI string, currentTime time.Time) bool {
+	if session.PreviousRefreshJTI == "" || presentedJTI != session.PreviousRefreshJTI {
+		return false
+	}
+
+	rotatedAt := time.UnixMilli(int64(session.LastRefreshAt))
+
+	return currentTime.Sub(rotatedAt) < constantAuth.RefreshTokenRotationGrace
+}
+
+// recordClient stamps the session with the client that is using it right now.
+func (u *sessionUsecaseImpl) recordClient(ctx context.Context, session *entity.Session, currentTime time.Time) {
+	userAgent := utils.GetUserAgentFromContext(ctx)
+	device := useragentutils.Parse(userAgent)
+
+	session.ClientIP = utils.GetClientIPFromContext(ctx)
+	session.UserAgent = userAgent
+	session.Browser = device.Browser
+	session.OS = device.OS
+	session.DeviceType = device.DeviceType
+	session.LastSeenAt = uint64(currentTime.UnixMilli())
+}
+
+func (u *sessionUsecaseImpl) logSession(ctx context.Context, session *entity.Session, msg string) {
+	logger.FromContext(ctx).WithFields(map[string]any{
+		"user_id":      session.UserID.String(),
+		"session_id":   session.SessionID.String(),
+		"login_method": session.LoginMethod,
+		"client_ip":    session.ClientIP,
+		"browser":      session.Browser,
+		"os":           session.OS,
+		"device_type":  session.DeviceType,
+	}).Info(msg)
+}
+
+func (u *sessionUsecaseImpl) save(ctx context.Context, session *entity.Session, currentTime time.Time) error {
+	ttl := u.sessionTTL(session, currentTime)
+	if err := u.sessionRepo.Save(ctx, session, ttl); err != nil {
+		return err
+	}
+
+	session.ExpiresAt = currentTime.Add(ttl)
+
+	return nil
+}
+
+func (u *sessionUsecaseImpl) sessionTTL(session *entity.Session, currentTime time.Time) time.Duration {
+	return utils.SessionTTL(u.cfg.Session, session, currentTime)
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	custom_typeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestSessionUsecase_RefreshReuseDetection(t *testing.T) {
+	tests := []struct {
+		name string
+		// present returns the refresh token to present after the session
+		// was created and refreshed once
+		present     func(t *testing.T, first, second *entity.Session, sessionRepo repository.SessionRepository) string
+		wantErr     string
+		wantRevoked bool
+	}{
+		{
+			name: "current token rotates",
+			present: func(t *testing.T, first, second *entity.Session, sessionRepo repository.SessionRepository) string {
+				return second.RefreshToken
+			},
+		},
+		{
+			name: "token rotated out moments ago",
+			present: func(t *testing.T, first, second *entity.Session, sessionRepo repository.SessionRepository) string {
+				return first.RefreshToken
+			},
+			wantErr: constantAuth.RefreshTokenRotatedErrorMessage,
+		},
+		{
+			name: "token rotated out after the grace period",
+			present: func(t *testing.T, first, second *entity.Session, sessionRepo repository.SessionRepository) string {
+				stored, _ := sessionRepo.Find(context.Background(), second.SessionID)
+				stored.LastRefreshAt = uint64(time.Now().Add(-constantAuth.RefreshTokenRotationGrace - time.Second).UnixMilli())
+				if err := sessionRepo.Save(context.Background(), stored, time.Hour); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+				return first.RefreshToken
+			},
+			wantErr:     constantAuth.RefreshTokenReusedErrorMessage,
+			wantRevoked: true,
+		},
+		{
+			name: "token two rotations old",
+			present: func(t *testing.T, first, second *entity.Session, sessionRepo repository.SessionRepository) string {
+				stored, _ := sessionRepo.Find(context.Background(), second.SessionID)
+				stored.PreviousRefreshJTI = uuid.NewString()
+				if err := sessionRepo.Save(context.Background(), stored, time.Hour); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+				return first.RefreshToken
+			},
+			wantErr:     constantAuth.RefreshTokenReusedErrorMessage,
+			wantRevoked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &entity.User{ID: uuid.New(), Status: custom_typeAuth.UserStatusActive}
+			usecase, _ := newTestSessionUsecase(newTestConfig(), user)
+
+			created, err := usecase.Create(ctx, user, entity.SessionOptions{})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			first := *created
+
+			second, err := usecase.Refresh(ctx, first.SessionID, first.RefreshToken, "")
+			if err != nil {
+				t.Fatalf("first Refresh() error = %v", err)
+			}
+			if second.RefreshJTI == first.RefreshJTI || second.PreviousRefreshJTI != first.RefreshJTI {
+				t.Fatalf("refresh did not rotate the refresh token")
+			}
+
+			refreshToken := tt.present(t, &first, second, usecase.sessionRepo)
+
+			_, err = usecase.Refresh(ctx, first.SessionID, refreshToken, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Refresh() error = %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Refresh() error = %v, want %q", err, tt.wantErr)
+			}
+
+			stored, err := usecase.sessionRepo.Find(ctx, first.SessionID)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if (stored == nil) != tt.wantRevoked {
+				t.Fatalf("session revoked = %v, want %v", stored == nil, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestSessionUsecase_RefreshRejectsForeignTokens(t *testing.T) {
+	ctx := context.Background()
+	user := &entity.User{ID: uuid.New(), Status: custom_typeAuth.UserStatusActive}
+	usecase, _ := newTestSessionUsecase(newTestConfig(), user)
+
+	session, err := usecase.Create(ctx, user, entity.SessionOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	other, err := usecase.Create(ctx, user, entity.SessionOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		clientID     string
+	}{
+		{name: "token of another session", refreshToken: other.RefreshToken},
+		{name: "access token", refreshToken: session.AccessToken},
+		{name: "garbage", refreshToken: "not-a-token"},
+		{name: "refreshed as a relying party", refreshToken: session.RefreshToken, clientID: "client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := usecase.Refresh(ctx, session.SessionID, tt.refreshToken, tt.clientID); err == nil {
+				t.Fatalf("Refresh() expected an error")
+			}
+
+			// a token that never belonged to the session is not a reuse
+			if stored, _ := usecase.sessionRepo.Find(ctx, session.SessionID); stored == nil {
+				t.Fatalf("session must not be revoked")
+			}
+		})
+	}
+}
+
+// racingSessionRepository runs between once after the sessions of a user were
+// read, the way a request landing in that window would.
+type racingSessionRepository struct {
+	repository.SessionRepository
+
+	between func()
+}
+
+func (r *racingSessionRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Session, error) {
+	sessions, err := r.SessionRepository.FindByUserID(ctx, userID)
+	if err == nil && r.between != nil {
+		r.between()
+		r.between = nil
+	}
+
+	return sessions, err
+}
+
+func TestSessionUsecase_RefreshAllKeepsConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("session refreshed in between", func(t *testing.T) {
+		user := &entity.User{ID: uuid.New(), Role: custom_typeAuth.RoleUser, Status: custom_typeAuth.UserStatusActive}
+		usecase, _ := newTestSessionUsecase(newTestConfig(), user)
+
+		session, err := usecase.Create(ctx, user, entity.SessionOptions{})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		var refreshed *entity.Session
+		sessionRepo := &racingSessionRepository{SessionRepository: usecase.sessionRepo}
+		sessionRepo.between = func() {
+			refreshed, err = usecase.Refresh(ctx, session.SessionID, session.RefreshToken, "")
+			if err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+		}
+		usecase.sessionRepo = sessionRepo
+
+		promoted := *user
+		promoted.Role = custom_typeAuth.RoleAdmin
+		if err := usecase.RefreshAll(ctx, &promoted); err != nil {
+			t.Fatalf("RefreshAll() error = %v", err)
+		}
+
+		stored, _ := usecase.sessionRepo.Find(ctx, session.SessionID)
+		if stored == nil || stored.RefreshJTI != refreshed.RefreshJTI {
+			t.Fatalf("RefreshAll() undid the concurrent refresh")
+		}
+		if stored.Role != custom_typeAuth.RoleAdmin {
+			t.Fatalf("session role = %v, want %v", stored.Role, custom_typeAuth.RoleAdmin)
+		}
+
+		if _, err := usecase.Refresh(ctx, session.SessionID, refreshed.RefreshToken, ""); err != nil {
+			t.Fatalf("Refresh() with the rotated token error = %v", err)
+		}
+	})
+
+	t.Run("session revoked in between", func(t *testing.T) {
+		user := &entity.User{ID: uuid.New(), Role: custom_typeAuth.RoleUser, Status: custom_typeAuth.UserStatusActive}
+		usecase, _ := newTestSessionUsecase(newTestConfig(), user)
+
+		session, err := usecase.Create(ctx, user, entity.SessionOptions{})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		sessionRepo := &racingSessionRepository{SessionRepository: usecase.sessionRepo}
+		sessionRepo.between = func() {
+			if err := usecase.Revoke(ctx, user.ID, session.SessionID); err != nil {
+				t.Fatalf("Revoke() error = %v", err)
+			}
+		}
+		usecase.sessionRepo = sessionRepo
+
+		if err := usecase.RefreshAll(ctx, user); err != nil {
+			t.Fatalf("RefreshAll() error = %v", err)
+		}
+
+		if stored, _ := usecase.sessionRepo.Find(ctx, session.SessionID); stored != nil {
+			t.Fatalf("RefreshAll() recreated a revoked session")
+		}
+	})
+}
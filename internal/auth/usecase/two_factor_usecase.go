@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/totputils"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorUsecase interface {
+	Enroll(ctx context.Context, userID uuid.UUID) (*entity.TOTPEnrollment, error)
+	Confirm(ctx context.Context, req *dto_request.ConfirmTwoFactor) error
+	Disable(ctx context.Context, req *dto_request.DisableTwoFactor) error
+	Reset(ctx context.Context, userID uuid.UUID) error
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	CreateChallenge(ctx context.Context, userID uuid.UUID, opts entity.SessionOptions) (*entity.LoginChallenge, error)
+	VerifyChallenge(ctx context.Context, req *dto_request.LoginTwoFactor) (*entity.LoginChallenge, error)
+}
+
+type twoFactorUsecaseImpl struct {
+	userRepo          repository.UserRepository
+	userTOTPRepo      repository.UserTOTPRepository
+	auditEventUsecase AuditEventUsecase
+	redisUtil         redisutils.RedisUtil
+	totpUtil          totputils.TOTPUtil
+	aesEncryptor      encryptutils.AESEncryptor
+	transactor        transactor.Transactor
+}
+
+func NewTwoFactorUsecase(
+	userRepo repository.UserRepository,
+	userTOTPRepo repository.UserTOTPRepository,
+	auditEventUsecase AuditEventUsecase,
+	redisUtil redisutils.RedisUtil,
+	totpUtil totputils.TOTPUtil,
+	aesEncryptor encryptutils.AESEncryptor,
+	transactor transactor.Transactor,
+) *twoFactorUsecaseImpl {
+	return &twoFactorUsecaseImpl{
+		userRepo:          userRepo,
+		userTOTPRepo:      userTOTPRepo,
+		auditEventUsecase: auditEventUsecase,
+		redisUtil:         redisUtil,
+		totpUtil:          totpUtil,
+		aesEncryptor:      aesEncryptor,
+		transactor:        transactor,
+	}
+}
+
+func (u *twoFactorUsecaseImpl) Enroll(ctx context.Context, userID uuid.UUID) (*entity.TOTPEnrollment, error) {
+	var enrollment *entity.TOTPEnrollment
+
+	err := u.transactor.Atomic(ctx, func(cForTx context.Context) error {
+		user, err := u.userRepo.Find(cForTx, "id", userID)
+		if err != nil {
+			return err
+		}
+
+		userTOTP, err := u.userTOTPRepo.FindByUserID(cForTx, userID)
+		if err != nil {
+			return err
+		}
+
+		if userTOTP != nil && userTOTP.IsEnabled {
+			return apperrorAuth.NewTwoFactorAlreadyEnabledError()
+		}
+
+		if userTOTP != nil {
+			if err := u.userTOTPRepo.DeleteByUserID(cForTx, userID); err != nil {
+				return err
+			}
+		}
+
+		secret, err := u.totpUtil.GenerateSecret()
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		encryptedSecret, err := u.aesEncryptor.Encrypt(secret)
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		if err := u.userTOTPRepo.Save(cForTx, &entity.UserTOTP{
+			UserID: userID,
+			Secret: encryptedSecret,
+		}); err != nil {
+			return err
+		}
+
+		uri := u.totpUtil.URI(user.Email, secret)
+		qrCode, err := u.totpUtil.QRCode(uri)
+		if err != nil {
+			return apperrorPkg.NewServerError(err)
+		}
+
+		enrollment = &entity.TOTPEnrollment{
+			Secret:     secret,
+			OtpauthURI: uri,
+			QRCode:     qrCode,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+func (u *twoFactorUsecaseImpl) Confirm(ctx context.Context, req *dto_request.ConfirmTwoFactor) error {
+	userTOTP, err := u.userTOTPRepo.FindByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if userTOTP == nil {
+		return apperrorAuth.NewTwoFactorNotEnrolledError()
+	}
+
+	if userTOTP.IsEnabled {
+		return apperrorAuth.NewTwoFactorAlreadyEnabledError()
+	}
+
+	attemptsKey, err := u.countAttempt(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	step, err := u.validateCode(userTOTP, req.Code)
+	if err != nil {
+		return err
+	}
+
+	userTOTP.LastUsedStep = step
+
+	err = u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		if err := u.userTOTPRepo.Enable(txCtx, userTOTP); err != nil {
+			return err
+		}
+
+		return u.auditEventUsecase.Record(txCtx, req.UserID, constantAuth.AuditEventTwoFactorEnabled, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.resetAttempts(ctx, attemptsKey)
+}
+
+func (u *twoFactorUsecaseImpl) Disable(ctx context.Context, req *dto_request.DisableTwoFactor) error {
+	userTOTP, err := u.findEnabled(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	attemptsKey, err := u.countAttempt(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.consumeCode(ctx, userTOTP, req.Code); err != nil {
+		return err
+	}
+
+	if err := u.delete(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	return u.resetAttempts(ctx, attemptsKey)
+}
+
+func (u *twoFactorUsecaseImpl) Reset(ctx context.Context, userID uuid.UUID) error {
+	if _, err := u.userRepo.Find(ctx, "id", userID); err != nil {
+		return err
+	}
+
+	return u.delete(ctx, userID)
+}
+
+func (u *twoFactorUsecaseImpl) delete(ctx context.Context, userID uuid.UUID) error {
+	return u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		if err := u.userTOTPRepo.DeleteByUserID(txCtx, userID); err != nil {
+			return err
+		}
+
+		return u.auditEventUsecase.Record(txCtx, userID, constantAuth.AuditEventTwoFactorDisabled, nil)
+	})
+}
+
+func (u *twoFactorUsecaseImpl) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	userTOTP, err := u.userTOTPRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return userTOTP != nil && userTOTP.IsEnabled, nil
+}
+
+func (u *twoFactorUsecaseImpl) CreateChallenge(ctx context.Context, userID uuid.UUID, opts entity.SessionOptions) (*entity.LoginChallenge, error) {
+	challenge := &entity.LoginChallenge{
+		ID:             uuid.New(),
+		UserID:         userID,
+		ExpiresAt:      time.Now().Add(constantAuth.LoginChallengeExpireDuration),
+		SessionOptions: opts,
+	}
+
+	if err := u.redisUtil.Set(ctx, utils.LoginChallengeKey(challenge.ID), challenge, constantAuth.LoginChallengeExpireDuration); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return challenge, nil
+}
+
+func (u *twoFactorUsecaseImpl) VerifyChallenge(ctx context.Context, req *dto_request.LoginTwoFactor) (*entity.LoginChallenge, error) {
+	challengeKey := utils.LoginChallengeKey(req.ChallengeID)
+
+	challenge := &entity.LoginChallenge{}
+	if err := u.redisUtil.GetWithScanJSON(ctx, challengeKey, challenge); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	remaining := time.Until(challenge.ExpiresAt)
+	if challenge.UserID == uuid.Nil || remaining <= 0 {
+		return nil, apperrorAuth.NewInvalidLoginChallengeError()
+	}
+
+	userTOTP, err := u.findEnabled(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// every attempt is counted before the code is checked, parallel guesses
+	// then cannot all slip in under the limit
+	attemptsKey := utils.LoginChallengeAttemptsKey(req.ChallengeID)
+	attempts, err := u.redisUtil.IncrWithExpire(ctx, attemptsKey, remaining)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	if attempts > constantAuth.LoginChallengeMaxAttempts {
+		_ = u.redisUtil.Delete(ctx, challengeKey, attemptsKey)
+		return nil, apperrorAuth.NewInvalidLoginChallengeError()
+	}
+
+	if err := u.consumeCode(ctx, userTOTP, req.Code); err != nil {
+		if attempts == constantAuth.LoginChallengeMaxAttempts {
+			_ = u.redisUtil.Delete(ctx, challengeKey, attemptsKey)
+			return nil, apperrorAuth.NewInvalidLoginChallengeError()
+		}
+		return nil, err
+	}
+
+	if err := u.redisUtil.Delete(ctx, challengeKey, attemptsKey); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return challenge, nil
+}
+
+// countAttempt counts a code entered by a signed-in user before it is
+// checked, an access token alone must not be enough to guess the code that
+// turns two-factor authentication off.
+func (u *twoFactorUsecaseImpl) countAttempt(ctx context.Context, userID uuid.UUID) (string, error) {
+	attemptsKey := utils.TwoFactorAttemptsKey(userID)
+
+	attempts, err := u.redisUtil.IncrWithExpire(ctx, attemptsKey, constantAuth.TwoFactorAttemptsWindow)
+	if err != nil {
+		return "", apperrorPkg.NewServerError(err)
+	}
+
+	if attempts > constantAuth.TwoFactorMaxAttempts {
+		retryAfter, err := u.redisUtil.TTL(ctx, attemptsKey)
+		if err != nil {
+			return "", apperrorPkg.NewServerError(err)
+		}
+		return "", apperrorAuth.NewTooManyTwoFactorAttemptsError(retryAfter)
+	}
+
+	return attemptsKey, nil
+}
+
+func (u *twoFactorUsecaseImpl) resetAttempts(ctx context.Context, attemptsKey string) error {
+	if err := u.redisUtil.Delete(ctx, attemptsKey); err != nil {
+		return apperrorPkg.NewServerError(err)
+	}
+
+	return nil
+}
+
+func (u *twoFactorUsecaseImpl) findEnabled(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error) {
+	userTOTP, err := u.userTOTPRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userTOTP == nil || !userTOTP.IsEnabled {
+		return nil, apperrorAuth.NewTwoFactorNotEnabledError()
+	}
+
+	return userTOTP, nil
+}
+
+func (u *twoFactorUsecaseImpl) consumeCode(ctx context.Context, userTOTP *entity.UserTOTP, code string) error {
+	step, err := u.validateCode(userTOTP, code)
+	if err != nil {
+		return err
+	}
+
+	// a code may only be used once, even inside its validity window
+	updated, err := u.userTOTPRepo.UpdateLastUsedStep(ctx, userTOTP.ID, step)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return apperrorAuth.NewInvalidTwoFactorCodeError()
+	}
+
+	return nil
+}
+
+func (u *twoFactorUsecaseImpl) validateCode(userTOTP *entity.UserTOTP, code string) (int64, error) {
+	secret, err := u.aesEncryptor.Decrypt(userTOTP.Secret)
+	if err != nil {
+		return 0, apperrorPkg.NewServerError(err)
+	}
+
+	step, ok := u.totpUtil.Validate(secret, code, time.Now())
+	if !ok {
+		return 0, apperrorAuth.NewInvalidTwoFactorCodeError()
+	}
+
+	return step, nil
+}
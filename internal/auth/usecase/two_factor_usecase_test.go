@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+
+	"github.com/google/uuid"
+)
+
+const testTOTPCode = "123456"
+
+func TestTwoFactorUsecase_VerifyChallengeAttemptCap(t *testing.T) {
+	tests := []struct {
+		name string
+		// codes are tried in order, only the outcome of the last one is
+		// checked against wantErr
+		codes     []string
+		wantErr   string
+		wantValid bool
+	}{
+		{
+			name:      "right code first",
+			codes:     []string{testTOTPCode},
+			wantValid: true,
+		},
+		{
+			name:      "right code on the last attempt",
+			codes:     append(repeat("000000", constantAuth.LoginChallengeMaxAttempts-1), testTOTPCode),
+			wantValid: true,
+		},
+		{
+			name:    "wrong code below the cap",
+			codes:   repeat("000000", constantAuth.LoginChallengeMaxAttempts-1),
+			wantErr: constantAuth.InvalidTwoFactorCodeErrorMessage,
+		},
+		{
+			name:    "wrong code on the last attempt",
+			codes:   repeat("000000", constantAuth.LoginChallengeMaxAttempts),
+			wantErr: constantAuth.InvalidLoginChallengeErrorMessage,
+		},
+		{
+			name:    "right code after the cap",
+			codes:   append(repeat("000000", constantAuth.LoginChallengeMaxAttempts), testTOTPCode),
+			wantErr: constantAuth.InvalidLoginChallengeErrorMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			userTOTP := &entity.UserTOTP{ID: uuid.New(), UserID: uuid.New(), IsEnabled: true}
+			usecase := NewTwoFactorUsecase(
+				newFakeUserRepository(),
+				newFakeUserTOTPRepository(userTOTP),
+				&fakeAuditEventUsecase{},
+				newFakeRedisUtil(),
+				&fakeTOTPUtil{code: testTOTPCode, step: 1},
+				&fakeAESEncryptor{},
+				nil,
+			)
+
+			challenge, err := usecase.CreateChallenge(ctx, userTOTP.UserID, entity.SessionOptions{})
+			if err != nil {
+				t.Fatalf("CreateChallenge() error = %v", err)
+			}
+
+			var verified *entity.LoginChallenge
+			for _, code := range tt.codes {
+				verified, err = usecase.VerifyChallenge(ctx, &dto_request.LoginTwoFactor{ChallengeID: challenge.ID, Code: code})
+			}
+
+			if tt.wantValid {
+				if err != nil || verified == nil || verified.UserID != userTOTP.UserID {
+					t.Fatalf("VerifyChallenge() = %v, %v, want the challenge", verified, err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("VerifyChallenge() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTwoFactorUsecase_VerifyChallengeRejectsReplay(t *testing.T) {
+	ctx := context.Background()
+	userTOTP := &entity.UserTOTP{ID: uuid.New(), UserID: uuid.New(), IsEnabled: true}
+	usecase := NewTwoFactorUsecase(
+		newFakeUserRepository(),
+		newFakeUserTOTPRepository(userTOTP),
+		&fakeAuditEventUsecase{},
+		newFakeRedisUtil(),
+		&fakeTOTPUtil{code: testTOTPCode, step: 1},
+		&fakeAESEncryptor{},
+		nil,
+	)
+
+	for i, wantErr := range []bool{false, true} {
+		challenge, err := usecase.CreateChallenge(ctx, userTOTP.UserID, entity.SessionOptions{})
+		if err != nil {
+			t.Fatalf("CreateChallenge() error = %v", err)
+		}
+
+		_, err = usecase.VerifyChallenge(ctx, &dto_request.LoginTwoFactor{ChallengeID: challenge.ID, Code: testTOTPCode})
+		if (err != nil) != wantErr {
+			t.Fatalf("use %d of the code: error = %v, wantErr %v", i+1, err, wantErr)
+		}
+	}
+}
+
+func TestTwoFactorUsecase_DisableAttemptCap(t *testing.T) {
+	tests := []struct {
+		name        string
+		codes       []string
+		wantErr     string
+		wantEnabled bool
+	}{
+		{
+			name:  "right code on the last attempt",
+			codes: append(repeat("000000", constantAuth.TwoFactorMaxAttempts-1), testTOTPCode),
+		},
+		{
+			name:        "wrong code below the cap",
+			codes:       repeat("000000", constantAuth.TwoFactorMaxAttempts),
+			wantErr:     constantAuth.InvalidTwoFactorCodeErrorMessage,
+			wantEnabled: true,
+		},
+		{
+			name:        "right code after the cap",
+			codes:       append(repeat("000000", constantAuth.TwoFactorMaxAttempts), testTOTPCode),
+			wantErr:     constantAuth.TooManyTwoFactorAttemptsErrorMessage,
+			wantEnabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			userTOTP := &entity.UserTOTP{ID: uuid.New(), UserID: uuid.New(), IsEnabled: true}
+			userTOTPRepo := newFakeUserTOTPRepository(userTOTP)
+			usecase := NewTwoFactorUsecase(
+				newFakeUserRepository(),
+				userTOTPRepo,
+				&fakeAuditEventUsecase{},
+				newFakeRedisUtil(),
+				&fakeTOTPUtil{code: testTOTPCode, step: 1},
+				&fakeAESEncryptor{},
+				&fakeTransactor{},
+			)
+
+			var err error
+			for _, code := range tt.codes {
+				err = usecase.Disable(ctx, &dto_request.DisableTwoFactor{UserID: userTOTP.UserID, Code: code})
+			}
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Disable() error = %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Disable() error = %v, want %q", err, tt.wantErr)
+			}
+
+			enabled, _ := usecase.IsEnabled(ctx, userTOTP.UserID)
+			if enabled != tt.wantEnabled {
+				t.Fatalf("IsEnabled() = %v, want %v", enabled, tt.wantEnabled)
+			}
+		})
+	}
+}
+
+func repeat(code string, count int) []string {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		codes = append(codes, code)
+	}
+
+	return codes
+}
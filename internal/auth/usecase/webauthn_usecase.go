@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
+	apperrorPkg "github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/webauthnutils"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+type WebAuthnUsecase interface {
+	BeginRegistration(ctx context.Context, userID uuid.UUID) (*entity.WebAuthnCeremony, error)
+	FinishRegistration(ctx context.Context, req *dto_request.FinishPasskeyRegistration) (*entity.WebAuthnCredential, error)
+	GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebAuthnCredential, error)
+	Rename(ctx context.Context, req *dto_request.RenamePasskey) (*entity.WebAuthnCredential, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	BeginLogin(ctx context.Context) (*entity.WebAuthnCeremony, error)
+	FinishLogin(ctx context.Context, req *dto_request.FinishPasskeyLogin) (*entity.User, *entity.Session, error)
+}
+
+type webAuthnUsecaseImpl struct {
+	userRepo               repository.UserRepository
+	userDetailRepo         repository.UserDetailRepository
+	webAuthnCredentialRepo repository.WebAuthnCredentialRepository
+	redisUtil              redisutils.RedisUtil
+	webAuthnUtil           webauthnutils.WebAuthnUtil
+	sessionUsecase         SessionUsecase
+	auditEventUsecase      AuditEventUsecase
+	transactor             transactor.Transactor
+}
+
+func NewWebAuthnUsecase(
+	userRepo repository.UserRepository,
+	userDetailRepo repository.UserDetailRepository,
+	webAuthnCredentialRepo repository.WebAuthnCredentialRepository,
+	redisUtil redisutils.RedisUtil,
+	webAuthnUtil webauthnutils.WebAuthnUtil,
+	sessionUsecase SessionUsecase,
+	auditEventUsecase AuditEventUsecase,
+	transactor transactor.Transactor,
+) *webAuthnUsecaseImpl {
+	return &webAuthnUsecaseImpl{
+		userRepo:               userRepo,
+		userDetailRepo:         userDetailRepo,
+		webAuthnCredentialRepo: webAuthnCredentialRepo,
+		redisUtil:              redisUtil,
+		webAuthnUtil:           webAuthnUtil,
+		sessionUsecase:         sessionUsecase,
+		auditEventUsecase:      auditEventUsecase,
+		transactor:             transactor,
+	}
+}
+
+func (u *webAuthnUsecaseImpl) BeginRegistration(ctx context.Context, userID uuid.UUID) (*entity.WebAuthnCeremony, error) {
+	user, err := u.webAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, sessionData, err := u.webAuthnUtil.BeginRegistration(user)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	if err := u.redisUtil.Set(ctx, utils.WebAuthnRegistrationKey(userID), sessionData, constantAuth.WebAuthnCeremonyExpireDuration); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return &entity.WebAuthnCeremony{
+		Options: creation,
+	}, nil
+}
+
+func (u *webAuthnUsecaseImpl) FinishRegistration(ctx context.Context, req *dto_request.FinishPasskeyRegistration) (*entity.WebAuthnCredential, error) {
+	sessionData, err := u.popSessionData(ctx, utils.WebAuthnRegistrationKey(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.webAuthnUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := u.webAuthnUtil.FinishRegistration(user, *sessionData, req.Credential)
+	if err != nil {
+		return nil, apperrorAuth.NewInvalidPasskeyError(err)
+	}
+
+	rawCredential, err := json.Marshal(credential)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	webAuthnCredential := &entity.WebAuthnCredential{
+		UserID:       req.UserID,
+		CredentialID: credential.ID,
+		Name:         req.Name,
+		Credential:   rawCredential,
+	}
+
+	err = u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		if err := u.webAuthnCredentialRepo.Save(txCtx, webAuthnCredential); err != nil {
+			return err
+		}
+
+		return u.auditEventUsecase.Record(txCtx, req.UserID, constantAuth.AuditEventPasskeyRegistered, &webAuthnCredential.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webAuthnCredential, nil
+}
+
+func (u *webAuthnUsecaseImpl) GetListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.WebAuthnCredential, error) {
+	return u.webAuthnCredentialRepo.FindByUserID(ctx, userID)
+}
+
+func (u *webAuthnUsecaseImpl) Rename(ctx context.Context, req *dto_request.RenamePasskey) (*entity.WebAuthnCredential, error) {
+	webAuthnCredential, err := u.webAuthnCredentialRepo.FindByID(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if webAuthnCredential == nil {
+		return nil, apperrorAuth.NewPasskeyNotFoundError()
+	}
+
+	webAuthnCredential.Name = req.Name
+
+	if err := u.webAuthnCredentialRepo.UpdateName(ctx, webAuthnCredential); err != nil {
+		return nil, err
+	}
+
+	return webAuthnCredential, nil
+}
+
+func (u *webAuthnUsecaseImpl) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	webAuthnCredential, err := u.webAuthnCredentialRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if webAuthnCredential == nil {
+		return apperrorAuth.NewPasskeyNotFoundError()
+	}
+
+	return u.transactor.Atomic(ctx, func(txCtx context.Context) error {
+		if err := u.webAuthnCredentialRepo.Delete(txCtx, id, userID); err != nil {
+			return err
+		}
+
+		return u.auditEventUsecase.Record(txCtx, userID, constantAuth.AuditEventPasskeyRemoved, &webAuthnCredential.Name)
+	})
+}
+
+func (u *webAuthnUsecaseImpl) BeginLogin(ctx context.Context) (*entity.WebAuthnCeremony, error) {
+	assertion, sessionData, err := u.webAuthnUtil.BeginLogin()
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	ceremonyID := uuid.New()
+	if err := u.redisUtil.Set(ctx, utils.WebAuthnLoginKey(ceremonyID), sessionData, constantAuth.WebAuthnCeremonyExpireDuration); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return &entity.WebAuthnCeremony{
+		ID:      ceremonyID,
+		Options: assertion,
+	}, nil
+}
+
+func (u *webAuthnUsecaseImpl) FinishLogin(ctx context.Context, req *dto_request.FinishPasskeyLogin) (*entity.User, *entity.Session, error) {
+	sessionData, err := u.popSessionData(ctx, utils.WebAuthnLoginKey(req.CeremonyID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var webAuthnCredential *entity.WebAuthnCredential
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		webAuthnCredential, err = u.webAuthnCredentialRepo.FindByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+
+		if webAuthnCredential == nil || !bytes.Equal(webAuthnCredential.UserID[:], userHandle) {
+			return nil, errors.New("unknown credential")
+		}
+
+		return u.webAuthnUser(ctx, webAuthnCredential.UserID)
+	}
+
+	credential, err := u.webAuthnUtil.FinishLogin(handler, *sessionData, req.Credential)
+	if err != nil {
+		return nil, nil, apperrorAuth.NewInvalidPasskeyError(err)
+	}
+
+	if credential.Authenticator.CloneWarning {
+		return nil, nil, apperrorAuth.NewInvalidPasskeyError(errors.New("authenticator sign count did not increase"))
+	}
+
+	rawCredential, err := json.Marshal(credential)
+	if err != nil {
+		return nil, nil, apperrorPkg.NewServerError(err)
+	}
+
+	webAuthnCredential.Credential = rawCredential
+	if err := u.webAuthnCredentialRepo.UpdateCredential(ctx, webAuthnCredential); err != nil {
+		return nil, nil, err
+	}
+
+	recordUserDB, err := u.userRepo.Find(ctx, "id", webAuthnCredential.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkUserStatus(recordUserDB); err != nil {
+		return nil, nil, err
+	}
+
+	session, err := u.sessionUsecase.Create(ctx, recordUserDB, entity.SessionOptions{
+		RememberMe:  req.RememberMe,
+		LoginMethod: constantAuth.LoginMethodPasskey,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return recordUserDB, session, nil
+}
+
+func (u *webAuthnUsecaseImpl) popSessionData(ctx context.Context, key string) (*webauthn.SessionData, error) {
+	val, err := u.redisUtil.GetDel(ctx, key)
+	if err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	if val == "" {
+		return nil, apperrorAuth.NewInvalidPasskeyCeremonyError()
+	}
+
+	sessionData := &webauthn.SessionData{}
+	if err := json.Unmarshal([]byte(val), sessionData); err != nil {
+		return nil, apperrorPkg.NewServerError(err)
+	}
+
+	return sessionData, nil
+}
+
+func (u *webAuthnUsecaseImpl) webAuthnUser(ctx context.Context, userID uuid.UUID) (*webauthnutils.User, error) {
+	recordUserDB, err := u.userRepo.Find(ctx, "id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := recordUserDB.Email
+	recordUserDetailDB, err := u.userDetailRepo.Find(ctx, "user_id", userID)
+	if err == nil && recordUserDetailDB != nil && recordUserDetailDB.FullName != "" {
+		displayName = recordUserDetailDB.FullName
+	}
+
+	webAuthnCredentials, err := u.webAuthnCredentialRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(webAuthnCredentials))
+	for _, webAuthnCredential := range webAuthnCredentials {
+		credential := webauthn.Credential{}
+		if err := json.Unmarshal(webAuthnCredential.Credential, &credential); err != nil {
+			return nil, apperrorPkg.NewServerError(err)
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return &webauthnutils.User{
+		ID:          recordUserDB.ID[:],
+		Name:        recordUserDB.Email,
+		DisplayName: displayName,
+		Credentials: credentials,
+	}, nil
+}
@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"strings"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+
+	"github.com/gin-gonic/gin"
+)
+
+func IsTokenMode(ctx *gin.Context) bool {
+	return strings.EqualFold(ctx.GetHeader(constantAuth.AUTH_MODE_HEADER), constantAuth.AUTH_MODE_TOKEN)
+}
@@ -11,6 +11,8 @@ import (
 const (
 	resetTokenKey        = "reset"
 	verificationTokenKey = "verification"
+	magicLinkKey         = "magic_link"
+	reactivationKey      = "reactivation"
 )
 
 func VerificationTokenCacheKey(email string) string {
@@ -21,6 +23,115 @@ func ResetTokenCacheKey(email string) string {
 	return fmt.Sprintf("%v:%v", email, resetTokenKey)
 }
 
+// MagicLinkCacheKey holds the cooldown of an email address, it is set whether
+// or not an account exists so the cooldown does not give accounts away.
+func MagicLinkCacheKey(email string) string {
+	return fmt.Sprintf("%v:%v", email, magicLinkKey)
+}
+
+func MagicLinkTokenKey(token uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.MAGIC_LINK, token)
+}
+
+// MagicLinkUserKey holds the only token of a user that may still sign in.
+func MagicLinkUserKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.MAGIC_LINK_USER, userID)
+}
+
+func PhoneOTPKey(purpose, subject string) string {
+	return fmt.Sprintf("%v:%v:%v", constantAuth.PHONE_OTP, purpose, subject)
+}
+
+func PhoneOTPAttemptsKey(purpose, subject string) string {
+	return fmt.Sprintf("%v:%v:%v", constantAuth.PHONE_OTP_ATTEMPTS, purpose, subject)
+}
+
+func ReactivationCacheKey(email string) string {
+	return fmt.Sprintf("%v:%v", email, reactivationKey)
+}
+
+func ReactivationTokenKey(token uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.REACTIVATION, token)
+}
+
+// InactiveUserKey marks an account that left the active status for the auth
+// middleware, which cannot afford a database lookup on every request.
+func InactiveUserKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.INACTIVE_USER, userID)
+}
+
 func SessionKey(sessionID uuid.UUID) string {
 	return fmt.Sprintf("%v:%v", constantAuth.SESSION_ID, sessionID)
 }
+
+// SessionKeyPrefix is SessionKey without the session id, the per-user index
+// holds the ids only.
+func SessionKeyPrefix() string {
+	return fmt.Sprintf("%v:", constantAuth.SESSION_ID)
+}
+
+func UserSessionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.USER_SESSIONS, userID)
+}
+
+func OIDCCodeKey(code string) string {
+	return fmt.Sprintf("%v:%v", constantAuth.OIDC_CODE, code)
+}
+
+func LoginChallengeKey(challengeID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.LOGIN_CHALLENGE, challengeID)
+}
+
+func LoginChallengeAttemptsKey(challengeID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.LOGIN_CHALLENGE_ATTEMPTS, challengeID)
+}
+
+func TwoFactorAttemptsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.TWO_FACTOR_ATTEMPTS, userID)
+}
+
+func WebAuthnRegistrationKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.WEBAUTHN_REGISTRATION, userID)
+}
+
+func WebAuthnLoginKey(ceremonyID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.WEBAUTHN_LOGIN, ceremonyID)
+}
+
+func LoginFailuresKey(scope, value string) string {
+	return fmt.Sprintf("%v:%v:%v", constantAuth.LOGIN_FAILURES, scope, value)
+}
+
+func LoginDelayKey(scope, value string) string {
+	return fmt.Sprintf("%v:%v:%v", constantAuth.LOGIN_DELAY, scope, value)
+}
+
+func LoginLockKey(scope, value string) string {
+	return fmt.Sprintf("%v:%v:%v", constantAuth.LOGIN_LOCK, scope, value)
+}
+
+func LoginFailedIPsKey(email string) string {
+	return fmt.Sprintf("%v:%v", constantAuth.LOGIN_FAILED_IPS, email)
+}
+
+func OauthLinkKey(state string) string {
+	return fmt.Sprintf("%v:%v", constantAuth.OAUTH_LINK, state)
+}
+
+func EmailChangeKey(token uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.EMAIL_CHANGE, token)
+}
+
+func UserEmailChangeKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.USER_EMAIL_CHANGE, userID)
+}
+
+// UserDataExportKey holds the cooldown of a user's export requests, an archive
+// is expensive to build.
+func UserDataExportKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.USER_DATA_EXPORT, userID)
+}
+
+func DataExportTokenKey(token uuid.UUID) string {
+	return fmt.Sprintf("%v:%v", constantAuth.DATA_EXPORT, token)
+}
@@ -36,3 +36,17 @@ func GetJTIFromContext(c context.Context) string {
 	}
 	return ""
 }
+
+func GetClientIPFromContext(c context.Context) string {
+	if clientIP, ok := c.Value(constant.ContextClientIP).(string); ok {
+		return clientIP
+	}
+	return ""
+}
+
+func GetUserAgentFromContext(c context.Context) string {
+	if userAgent, ok := c.Value(constant.ContextUserAgent).(string); ok {
+		return userAgent
+	}
+	return ""
+}
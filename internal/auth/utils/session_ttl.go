@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"time"
+
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+)
+
+// SessionTTL returns how long the session may live from currentTime on: its
+// idle timeout, capped by what is left of its absolute lifetime. A result
+// <= 0 means the session has reached its absolute lifetime.
+func SessionTTL(cfg *config.SessionConfig, session *entity.Session, currentTime time.Time) time.Duration {
+	idleTimeout, maxAge := cfg.IdleTimeout, cfg.MaxAge
+	if session.RememberMe {
+		idleTimeout, maxAge = cfg.RememberMeIdleTimeout, cfg.RememberMeMaxAge
+	}
+
+	ttl := constantAuth.SessionExpireDuration
+	if idleTimeout > 0 {
+		ttl = time.Duration(idleTimeout) * time.Minute
+	}
+
+	if maxAge <= 0 {
+		return ttl
+	}
+
+	remaining := time.UnixMilli(int64(session.LoginAt)).Add(time.Duration(maxAge) * time.Minute).Sub(currentTime)
+
+	return min(remaining, ttl)
+}
@@ -1,17 +1,22 @@
 package utils
 
 import (
+	"math"
+	"time"
+
 	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-func SetSessionCookie(ctx *gin.Context, sessionID uuid.UUID) {
+// SetSessionCookie keeps the cookie Max-Age in line with the Redis TTL of the
+// session so the browser drops the cookie when the session expires.
+func SetSessionCookie(ctx *gin.Context, sessionID uuid.UUID, maxAge time.Duration) {
 	ctx.SetCookie(
 		constantAuth.SESSION_NAME,
 		sessionID.String(),
-		86400, // 1 hari
+		int(math.Ceil(maxAge.Seconds())),
 		"/",
 		"",
 		true, // secure
@@ -1,41 +1,81 @@
 package provider
 
 import (
+	"context"
+	"log"
+
 	controllerAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/controller"
 	repositoryAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/repository"
 	routeAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/route"
 	usecaseAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
 
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	authUserRepository              repositoryAuth.UserRepository
-	authUserDetailRepository        repositoryAuth.UserDetailRepository
-	authResetTokenRepository        repositoryAuth.ResetTokenRepository
-	authVerificationTokenRepository repositoryAuth.VerificationTokenRepository
+	authUserRepository               repositoryAuth.UserRepository
+	authUserDetailRepository         repositoryAuth.UserDetailRepository
+	authResetTokenRepository         repositoryAuth.ResetTokenRepository
+	authVerificationTokenRepository  repositoryAuth.VerificationTokenRepository
+	authSessionRepository            repositoryAuth.SessionRepository
+	authUserTOTPRepository           repositoryAuth.UserTOTPRepository
+	authWebAuthnCredentialRepository repositoryAuth.WebAuthnCredentialRepository
+	authPasswordHistoryRepository    repositoryAuth.PasswordHistoryRepository
+	authUserStatusHistoryRepository  repositoryAuth.UserStatusHistoryRepository
+	authAccountPurgeRepository       repositoryAuth.AccountPurgeRepository
+	authUserIdentityRepository       repositoryAuth.UserIdentityRepository
+	authUserAuditEventRepository     repositoryAuth.UserAuditEventRepository
 )
 
 var (
-	authAuthUsecase usecaseAuth.AuthUsecase
-	profileUsecase  usecaseAuth.ProfileUsecase
-	oauthUsecase    usecaseAuth.OauthUsecase
+	authAuthUsecase        usecaseAuth.AuthUsecase
+	profileUsecase         usecaseAuth.ProfileUsecase
+	oauthUsecase           usecaseAuth.OauthUsecase
+	sessionUsecase         usecaseAuth.SessionUsecase
+	oidcUsecase            usecaseAuth.OidcUsecase
+	twoFactorUsecase       usecaseAuth.TwoFactorUsecase
+	loginAttemptUsecase    usecaseAuth.LoginAttemptUsecase
+	webAuthnUsecase        usecaseAuth.WebAuthnUsecase
+	passwordHistoryUsecase usecaseAuth.PasswordHistoryUsecase
+	phoneOTPUsecase        usecaseAuth.PhoneOTPUsecase
+	accountStatusUsecase   usecaseAuth.AccountStatusUsecase
+	accountDeletionUsecase usecaseAuth.AccountDeletionUsecase
+	dataExportUsecase      usecaseAuth.DataExportUsecase
+	identityUsecase        usecaseAuth.IdentityUsecase
+	auditEventUsecase      usecaseAuth.AuditEventUsecase
 )
 
 var (
-	authAuthController *controllerAuth.AuthController
-	profileController  *controllerAuth.ProfileController
-	oauthController    *controllerAuth.OauthController
+	authAuthController   *controllerAuth.AuthController
+	profileController    *controllerAuth.ProfileController
+	oauthController      *controllerAuth.OauthController
+	sessionController    *controllerAuth.SessionController
+	wellKnownController  *controllerAuth.WellKnownController
+	oidcController       *controllerAuth.OidcController
+	twoFactorController  *controllerAuth.TwoFactorController
+	webAuthnController   *controllerAuth.WebAuthnController
+	dataExportController *controllerAuth.DataExportController
+	identityController   *controllerAuth.IdentityController
 )
 
 func ProvideAuthModule(router *gin.Engine) {
 	injectAuthModuleRepository()
 	injectAuthModuleUseCase()
 	injectAuthModuleController()
+	scheduleAuthJobs()
 
-	routeAuth.AuthControllerRoute(authAuthController, router, authMiddleware)
-	routeAuth.ProfileControlRoute(profileController, router, authMiddleware)
-	routeAuth.OauthControllerRoute(oauthController, router)
+	routeAuth.AuthControllerRoute(authAuthController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.ProfileControlRoute(profileController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.SessionControllerRoute(sessionController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.TwoFactorControllerRoute(twoFactorController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.WebAuthnControllerRoute(webAuthnController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.DataExportControllerRoute(dataExportController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.IdentityControllerRoute(identityController, router, authMiddleware, rateLimitMiddleware)
+	routeAuth.OauthControllerRoute(oauthController, router, authMiddleware)
+	routeAuth.WellKnownControllerRoute(wellKnownController, router)
+	routeAuth.OidcControllerRoute(oidcController, router, authMiddleware, rateLimitMiddleware)
 }
 
 func injectAuthModuleRepository() {
@@ -43,15 +83,59 @@ func injectAuthModuleRepository() {
 	authUserDetailRepository = repositoryAuth.NewUserDetailRepository(dbWrapper, cfgConfig)
 	authResetTokenRepository = repositoryAuth.NewResetTokenRepository(dbWrapper)
 	authVerificationTokenRepository = repositoryAuth.NewVerificationTokenRepository(dbWrapper)
+	authSessionRepository = repositoryAuth.NewSessionRepository(redisUtil)
+	authUserTOTPRepository = repositoryAuth.NewUserTOTPRepository(dbWrapper)
+	authWebAuthnCredentialRepository = repositoryAuth.NewWebAuthnCredentialRepository(dbWrapper)
+	authPasswordHistoryRepository = repositoryAuth.NewPasswordHistoryRepository(dbWrapper)
+	authUserStatusHistoryRepository = repositoryAuth.NewUserStatusHistoryRepository(dbWrapper)
+	authAccountPurgeRepository = repositoryAuth.NewAccountPurgeRepository(dbWrapper)
+	authUserIdentityRepository = repositoryAuth.NewUserIdentityRepository(dbWrapper)
+	authUserAuditEventRepository = repositoryAuth.NewUserAuditEventRepository(dbWrapper)
 }
 
 func injectAuthModuleUseCase() {
+	auditEventUsecase = usecaseAuth.NewAuditEventUsecase(authUserAuditEventRepository)
+	sessionUsecase = usecaseAuth.NewSessionUsecase(authUserRepository, authSessionRepository, auditEventUsecase, jwtUtil, cfgConfig)
+	twoFactorUsecase = usecaseAuth.NewTwoFactorUsecase(
+		authUserRepository,
+		authUserTOTPRepository,
+		auditEventUsecase,
+		redisUtil,
+		totpUtil,
+		aesEncryptor,
+		store,
+	)
+	loginAttemptUsecase = usecaseAuth.NewLoginAttemptUsecase(authUserRepository, redisUtil, cfgConfig)
+	passwordHistoryUsecase = usecaseAuth.NewPasswordHistoryUsecase(authPasswordHistoryRepository, passwordEncryptor, cfgConfig)
+	phoneOTPUsecase = usecaseAuth.NewPhoneOTPUsecase(redisUtil, smsTask, cfgConfig)
+	accountStatusUsecase = usecaseAuth.NewAccountStatusUsecase(authUserRepository, authUserStatusHistoryRepository, redisUtil, sessionUsecase)
+	accountDeletionUsecase = usecaseAuth.NewAccountDeletionUsecase(
+		authUserRepository,
+		authUserDetailRepository,
+		authAccountPurgeRepository,
+		accountStatusUsecase,
+		redisUtil,
+		cloudinaryUtil,
+		emailTask,
+		store,
+		cfgConfig,
+	)
+	dataExportUsecase = newDataExportUsecase()
 	authAuthUsecase = usecaseAuth.NewAuthUsecase(
 		authUserRepository,
 		authUserDetailRepository,
+		authUserIdentityRepository,
 		redisUtil,
 		jwtUtil,
+		sessionUsecase,
+		twoFactorUsecase,
+		loginAttemptUsecase,
+		passwordHistoryUsecase,
+		phoneOTPUsecase,
+		accountStatusUsecase,
+		auditEventUsecase,
 		passwordEncryptor,
+		breachChecker,
 		base64Encryptor,
 		emailTask,
 		authResetTokenRepository,
@@ -63,13 +147,99 @@ func injectAuthModuleUseCase() {
 		authUserDetailRepository,
 		redisUtil,
 		passwordEncryptor,
+		breachChecker,
+		sessionUsecase,
+		passwordHistoryUsecase,
+		phoneOTPUsecase,
+		accountStatusUsecase,
+		accountDeletionUsecase,
+		emailTask,
+		store,
+	)
+	oauthUsecase = usecaseAuth.NewOauthUsecase(
+		authUserRepository,
+		authUserDetailRepository,
+		authUserIdentityRepository,
+		redisUtil,
+		sessionUsecase,
+		twoFactorUsecase,
+		auditEventUsecase,
+		store,
+	)
+	identityUsecase = usecaseAuth.NewIdentityUsecase(
+		authUserRepository,
+		authUserIdentityRepository,
+		authWebAuthnCredentialRepository,
+		auditEventUsecase,
+		redisUtil,
+		store,
+		cfgConfig,
+	)
+	webAuthnUsecase = usecaseAuth.NewWebAuthnUsecase(
+		authUserRepository,
+		authUserDetailRepository,
+		authWebAuthnCredentialRepository,
+		redisUtil,
+		webAuthnUtil,
+		sessionUsecase,
+		auditEventUsecase,
 		store,
 	)
-	oauthUsecase = usecaseAuth.NewOauthUsecase(authUserRepository, authUserDetailRepository, redisUtil, jwtUtil, store)
+	oidcUsecase = usecaseAuth.NewOidcUsecase(
+		authUserRepository,
+		authUserDetailRepository,
+		authSessionRepository,
+		redisUtil,
+		jwtUtil,
+		sessionUsecase,
+		cfgConfig,
+	)
 }
 
 func injectAuthModuleController() {
 	authAuthController = controllerAuth.NewAuthController(authAuthUsecase, store)
 	profileController = controllerAuth.NewProfileController(profileUsecase, store)
 	oauthController = controllerAuth.NewOauthController(oauthUsecase, cfgConfig)
+	sessionController = controllerAuth.NewSessionController(sessionUsecase)
+	wellKnownController = controllerAuth.NewWellKnownController(jwtUtil, cfgConfig)
+	oidcController = controllerAuth.NewOidcController(oidcUsecase, cfgConfig)
+	twoFactorController = controllerAuth.NewTwoFactorController(twoFactorUsecase)
+	webAuthnController = controllerAuth.NewWebAuthnController(webAuthnUsecase)
+	dataExportController = controllerAuth.NewDataExportController(dataExportUsecase)
+	identityController = controllerAuth.NewIdentityController(identityUsecase)
+}
+
+// newDataExportUsecase builds its own repositories, the queue worker runs the
+// export without the rest of the auth module.
+func newDataExportUsecase() usecaseAuth.DataExportUsecase {
+	return usecaseAuth.NewDataExportUsecase(
+		repositoryAuth.NewUserRepository(dbWrapper),
+		repositoryAuth.NewUserDetailRepository(dbWrapper, cfgConfig),
+		repositoryAuth.NewUserIdentityRepository(dbWrapper),
+		repositoryAuth.NewSessionRepository(redisUtil),
+		repositoryAuth.NewUserStatusHistoryRepository(dbWrapper),
+		repositoryAuth.NewPasswordHistoryRepository(dbWrapper),
+		repositoryAuth.NewUserTOTPRepository(dbWrapper),
+		repositoryAuth.NewWebAuthnCredentialRepository(dbWrapper),
+		repositoryAuth.NewVerificationTokenRepository(dbWrapper),
+		repositoryAuth.NewResetTokenRepository(dbWrapper),
+		usecaseAuth.NewAuditEventUsecase(repositoryAuth.NewUserAuditEventRepository(dbWrapper)),
+		redisUtil,
+		base64Encryptor,
+		objectStorage,
+		dataExportTask,
+		emailTask,
+		cfgConfig,
+	)
+}
+
+func scheduleAuthJobs() {
+	_, err := cronJob.AddFunc("@every 1h", func() {
+		if err := accountDeletionUsecase.PurgeDue(context.Background()); err != nil {
+			logger.Log.Errorf("error purging accounts past their deletion grace period: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("error scheduling account purge job: %v", err)
+	}
 }
@@ -45,7 +45,7 @@ func InitProvider(cfg *config.Config) {
 }
 
 func ProvideHttpDependency(cfg *config.Config, router *gin.Engine) {
-	if emailTask == nil {
+	if emailTask == nil || smsTask == nil || dataExportTask == nil {
 		injectQueueModuleTask(asynqClient)
 	}
 	ProvideGatewayModule(router)
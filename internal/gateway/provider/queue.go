@@ -10,11 +10,15 @@ import (
 )
 
 var (
-	emailTask tasks.EmailTask
+	emailTask      tasks.EmailTask
+	smsTask        tasks.SMSTask
+	dataExportTask tasks.DataExportTask
 )
 
 var (
-	emailTaskProcessor *processor.EmailTaskProcessor
+	emailTaskProcessor      *processor.EmailTaskProcessor
+	smsTaskProcessor        *processor.SMSTaskProcessor
+	dataExportTaskProcessor *processor.DataExportTaskProcessor
 )
 
 func ProvideQueueModule(client *asynq.Client, mux *asynq.ServeMux, cfg *config.Config) {
@@ -22,14 +26,24 @@ func ProvideQueueModule(client *asynq.Client, mux *asynq.ServeMux, cfg *config.C
 	injectQueueModuleProcessor(cfg)
 
 	route.EmailTaskRoute(mux, emailTaskProcessor)
+	route.SMSTaskRoute(mux, smsTaskProcessor)
+	route.DataExportTaskRoute(mux, dataExportTaskProcessor)
 }
 
 func injectQueueModuleTask(client *asynq.Client) {
 	if emailTask == nil {
 		emailTask = tasks.NewEmailTask(client)
 	}
+	if smsTask == nil {
+		smsTask = tasks.NewSMSTask(client)
+	}
+	if dataExportTask == nil {
+		dataExportTask = tasks.NewDataExportTask(client)
+	}
 }
 
 func injectQueueModuleProcessor(cfg *config.Config) {
 	emailTaskProcessor = processor.NewEmailTaskProcessor(base64Encryptor, smtpUtil, cfg)
+	smsTaskProcessor = processor.NewSMSTaskProcessor(smsSender, cfg)
+	dataExportTaskProcessor = processor.NewDataExportTaskProcessor(newDataExportUsecase())
 }
@@ -7,43 +7,102 @@ import (
 
 	"github.com/faisalyudiansah/auth-service-template/pkg/config"
 	"github.com/faisalyudiansah/auth-service-template/pkg/database/transactor"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
 	"github.com/faisalyudiansah/auth-service-template/pkg/middleware"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/breachutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/cloudinaryutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/encryptutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ratelimitutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/smsutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/smtputils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/storageutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/totputils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/webauthnutils"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron/v3"
 )
 
 var (
-	cloudinaryUtil    cloudinaryutils.CloudinaryUtil
-	jwtUtil           jwtutils.JwtUtilInterface
-	smtpUtil          smtputils.SMTPUtils
-	redisUtil         redisutils.RedisUtil
-	passwordEncryptor encryptutils.PasswordEncryptor
-	base64Encryptor   encryptutils.Base64Encryptor
-	store             transactor.Transactor
-	authMiddleware    *middleware.AuthMiddleware
-	cronJob           *cron.Cron
+	cloudinaryUtil      cloudinaryutils.CloudinaryUtil
+	jwtUtil             jwtutils.JwtUtilInterface
+	smtpUtil            smtputils.SMTPUtils
+	smsSender           smsutils.SMSSender
+	redisUtil           redisutils.RedisUtil
+	passwordEncryptor   encryptutils.PasswordEncryptor
+	breachChecker       breachutils.BreachChecker
+	base64Encryptor     encryptutils.Base64Encryptor
+	aesEncryptor        encryptutils.AESEncryptor
+	totpUtil            totputils.TOTPUtil
+	webAuthnUtil        webauthnutils.WebAuthnUtil
+	rateLimiter         ratelimitutils.RateLimiter
+	objectStorage       storageutils.Storage
+	store               transactor.Transactor
+	authMiddleware      *middleware.AuthMiddleware
+	rateLimitMiddleware *middleware.RateLimitMiddleware
+	cronJob             *cron.Cron
 )
 
 func ProvideUtils(cfg *config.Config, db *sql.DB, rdb *redis.Client) {
 	cloudinaryUtil = cloudinaryutils.NewCloudinaryUtil()
 	jwtUtil = jwtutils.NewJwtUtil(cfg.Jwt)
 	smtpUtil = smtputils.NewSMTPUtils(cfg.SMTP)
-	passwordEncryptor = encryptutils.NewBcryptPasswordEncryptor(cfg.App.BCryptCost)
+	if cfg.SMS.Sender == smsutils.SMSSenderWebhook {
+		smsSender = smsutils.NewWebhookSMSSender(cfg.SMS)
+	} else {
+		smsSender = smsutils.NewLogSMSSender(cfg.SMS)
+	}
+	passwordEncryptor = encryptutils.NewPHCPasswordEncryptor(cfg.PasswordHash, cfg.App.BCryptCost)
+	breachChecker = breachutils.NewBreachChecker(cfg.PasswordPolicy)
 	base64Encryptor = encryptutils.NewBase64Encryptor()
+	aesEncryptor = encryptutils.NewAESGCMEncryptor(cfg.TOTP.EncryptionKey)
+	totpUtil = totputils.NewTOTPUtil(cfg.TOTP.Issuer)
+	webAuthnUtil = webauthnutils.NewWebAuthnUtil(cfg.WebAuthn)
 	redisUtil = redisutils.NewRedisUtils(cfg.Redis, rdb)
+	rateLimiter = ratelimitutils.NewRateLimiter(rdb)
+	if cfg.Storage.Backend == storageutils.StorageBackendS3 {
+		s3Storage, err := storageutils.NewS3Storage(cfg.Storage)
+		if err != nil {
+			log.Fatalf("error creating s3 storage: %v", err)
+		}
+		objectStorage = s3Storage
+	} else {
+		objectStorage = storageutils.NewLocalStorage(cfg.Storage)
+	}
 	store = transactor.NewTransactor(dbWrapper)
 
-	authMiddleware = middleware.NewAuthMiddleware(redisUtil, jwtUtil)
+	authMiddleware = middleware.NewAuthMiddleware(redisUtil, jwtUtil, cfg)
+	rateLimitMiddleware = middleware.NewRateLimitMiddleware(rateLimiter, cfg)
 
 	wib, err := time.LoadLocation("Asia/Jakarta")
 	if err != nil {
 		log.Fatalf("Failed to load WIB timezone: %v", err)
 	}
 	cronJob = cron.New(cron.WithLocation(wib))
+
+	scheduleUtilJobs()
+}
+
+func ProvideRateLimitMiddleware() *middleware.RateLimitMiddleware {
+	return rateLimitMiddleware
+}
+
+func scheduleUtilJobs() {
+	if _, err := cronJob.AddFunc("@every 1m", func() {
+		if err := jwtUtil.ReloadKeys(); err != nil {
+			logger.Log.Errorf("error reloading jwt signing keys: %v", err)
+		}
+	}); err != nil {
+		log.Fatalf("error scheduling jwt signing keys reload: %v", err)
+	}
+
+	if _, err := cronJob.AddFunc("@every 10m", func() {
+		if err := breachChecker.Reload(); err != nil {
+			logger.Log.Errorf("error reloading breached password dataset: %v", err)
+		}
+	}); err != nil {
+		log.Fatalf("error scheduling breached password dataset reload: %v", err)
+	}
 }
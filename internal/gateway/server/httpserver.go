@@ -11,6 +11,8 @@ import (
 	"github.com/faisalyudiansah/auth-service-template/pkg/config"
 	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
 	"github.com/faisalyudiansah/auth-service-template/pkg/middleware"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/oauthutils"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ratelimitutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/validationutils"
 
 	"github.com/gin-contrib/cors"
@@ -18,11 +20,8 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/sessions"
-	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
-	"github.com/markbates/goth/providers/google"
 	"github.com/shopspring/decimal"
-	"golang.org/x/time/rate"
 )
 
 type HttpServer struct {
@@ -43,9 +42,7 @@ func NewHttpServer(cfg *config.Config) *HttpServer {
 
 	gothic.Store = store
 
-	goth.UseProviders(
-		google.New(cfg.Google.ClientID, cfg.Google.ClientSecret, cfg.Google.CallbackURL),
-	)
+	oauthutils.UseProviders(cfg)
 
 	router := gin.New()
 	router.ContextWithFallback = true
@@ -105,17 +102,23 @@ func RegisterValidators() {
 }
 
 func RegisterMiddleware(router *gin.Engine, cfg *config.Config) {
-	limiter := rate.NewLimiter(rate.Limit(cfg.HttpServer.MaxRequestPerSecond), cfg.HttpServer.MaxRequestPerSecond)
+	// ClientIP feeds the rate limits and login lockouts, so forwarded headers
+	// are only honoured when they come from a configured proxy
+	if err := router.SetTrustedProxies(cfg.HttpServer.TrustedProxies); err != nil {
+		logger.Log.Fatal("Error setting trusted proxies:", err)
+	}
+
+	rateLimitMiddleware := provider.ProvideRateLimitMiddleware()
 
 	middlewares := []gin.HandlerFunc{
 		middleware.Logger(),
 		middleware.Metrics(),
 		middleware.ErrorHandler(),
-		middleware.RateLimiter(limiter),
+		rateLimitMiddleware.RateLimiter(ratelimitutils.PolicyGlobal),
 		middleware.RequestTimeout(cfg),
 		cors.New(cors.Config{
 			AllowMethods: []string{"*"},
-			AllowHeaders: []string{"*", "Authorization", "Content-Type"},
+			AllowHeaders: []string{"*", "Authorization", "Content-Type", "X-Auth-Mode"},
 			AllowOrigins: []string{
 				cfg.URLClientConfig.URLCientVerificationEmail,
 				cfg.URLClientConfig.URLClientForgotPassword,
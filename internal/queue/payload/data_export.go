@@ -0,0 +1,13 @@
+package payload
+
+import "github.com/google/uuid"
+
+type DataExportPayload struct {
+	UserID      uuid.UUID `json:"user_id"`
+	RequestedBy uuid.UUID `json:"requested_by"`
+	Format      string    `json:"format"`
+}
+
+type DataExportCleanupPayload struct {
+	StorageKey string `json:"storage_key"`
+}
@@ -9,3 +9,39 @@ type ForgotPasswordEmailPayload struct {
 	Email string `json:"email"`
 	Token string `json:"token"`
 }
+
+type MagicLinkEmailPayload struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type ReactivationEmailPayload struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type AccountDeletionEmailPayload struct {
+	Email    string `json:"email"`
+	Token    string `json:"token"`
+	DeleteAt string `json:"delete_at"`
+}
+
+type PasswordChangedEmailPayload struct {
+	Email string `json:"email"`
+}
+
+type EmailChangeConfirmationPayload struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type EmailChangeNoticePayload struct {
+	Email    string `json:"email"`
+	NewEmail string `json:"new_email"`
+}
+
+type DataExportEmailPayload struct {
+	Email    string `json:"email"`
+	Token    string `json:"token"`
+	ExpireAt string `json:"expire_at"`
+}
@@ -0,0 +1,6 @@
+package payload
+
+type OTPSMSPayload struct {
+	PhoneNumber string `json:"phone_number"`
+	Code        string `json:"code"`
+}
@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+
+	dto_request "github.com/faisalyudiansah/auth-service-template/internal/auth/dto/request"
+	usecaseAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/usecase"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+
+	"github.com/hibiken/asynq"
+)
+
+type DataExportTaskProcessor struct {
+	dataExportUsecase usecaseAuth.DataExportUsecase
+}
+
+func NewDataExportTaskProcessor(
+	dataExportUsecase usecaseAuth.DataExportUsecase,
+) *DataExportTaskProcessor {
+	return &DataExportTaskProcessor{
+		dataExportUsecase: dataExportUsecase,
+	}
+}
+
+func (p *DataExportTaskProcessor) HandleBuild(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.DataExportPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	return p.dataExportUsecase.Build(ctx, &dto_request.RequestDataExport{
+		Format:      payload.Format,
+		UserID:      payload.UserID,
+		RequestedBy: payload.RequestedBy,
+	})
+}
+
+func (p *DataExportTaskProcessor) HandleCleanup(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.DataExportCleanupPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	return p.dataExportUsecase.Cleanup(ctx, payload.StorageKey)
+}
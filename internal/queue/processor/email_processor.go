@@ -72,3 +72,148 @@ func (p *EmailTaskProcessor) HandleForgotPasswordEmail(ctx context.Context, t *a
 
 	return err
 }
+
+func (p *EmailTaskProcessor) HandleMagicLinkEmail(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.MagicLinkEmailPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	// requests for addresses without an account are queued without a token
+	// only to keep their timing the same, nothing is sent for them
+	if payload.Token == "" {
+		return nil
+	}
+
+	encodedToken := p.base64Encryptor.EncodeURL(payload.Token)
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.MagicLinkSubject,
+		smtputils.MagicLinkTemplate,
+		map[string]any{
+			"Link": fmt.Sprintf("%s/magic-link?token=%v", p.cfg.URLClientConfig.URLClientMagicLink, encodedToken),
+		},
+	)
+
+	return err
+}
+
+func (p *EmailTaskProcessor) HandlePasswordChangedEmail(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.PasswordChangedEmailPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.PasswordChangedSubject,
+		smtputils.PasswordChangedTemplate,
+		map[string]any{},
+	)
+
+	return err
+}
+
+func (p *EmailTaskProcessor) HandleEmailChangeConfirmation(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.EmailChangeConfirmationPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	encodedToken := p.base64Encryptor.EncodeURL(payload.Token)
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.EmailChangeConfirmSubject,
+		smtputils.EmailChangeConfirmTemplate,
+		map[string]any{
+			"Link": fmt.Sprintf("%s/confirm-email-change?token=%v", p.cfg.URLClientConfig.URLClientChangeEmail, encodedToken),
+		},
+	)
+
+	return err
+}
+
+func (p *EmailTaskProcessor) HandleEmailChangeNotice(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.EmailChangeNoticePayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.EmailChangeNoticeSubject,
+		smtputils.EmailChangeNoticeTemplate,
+		map[string]any{
+			"NewEmail": payload.NewEmail,
+		},
+	)
+
+	return err
+}
+
+func (p *EmailTaskProcessor) HandleReactivationEmail(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.ReactivationEmailPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	encodedToken := p.base64Encryptor.EncodeURL(payload.Token)
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.ReactivationSubject,
+		smtputils.ReactivationTemplate,
+		map[string]any{
+			"Link": fmt.Sprintf("%s/reactivate-account?token=%v", p.cfg.URLClientConfig.URLClientReactivation, encodedToken),
+		},
+	)
+
+	return err
+}
+
+func (p *EmailTaskProcessor) HandleAccountDeletionEmail(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.AccountDeletionEmailPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	// keeping the account is a reactivation, so the link lands on the same page
+	encodedToken := p.base64Encryptor.EncodeURL(payload.Token)
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.AccountDeletionSubject,
+		smtputils.AccountDeletionTemplate,
+		map[string]any{
+			"Link":     fmt.Sprintf("%s/reactivate-account?token=%v", p.cfg.URLClientConfig.URLClientReactivation, encodedToken),
+			"DeleteAt": payload.DeleteAt,
+		},
+	)
+
+	return err
+}
+
+func (p *EmailTaskProcessor) HandleDataExportEmail(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.DataExportEmailPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	encodedToken := p.base64Encryptor.EncodeURL(payload.Token)
+	err := p.smtpUtil.SendMailHTMLContext(
+		ctx,
+		payload.Email,
+		smtputils.DataExportSubject,
+		smtputils.DataExportTemplate,
+		map[string]any{
+			"Link":     fmt.Sprintf("%s/data-export#token=%v", p.cfg.URLClientConfig.URLClientDataExport, encodedToken),
+			"ExpireAt": payload.ExpireAt,
+		},
+	)
+
+	return err
+}
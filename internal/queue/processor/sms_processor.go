@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/smsutils"
+
+	"github.com/hibiken/asynq"
+)
+
+type SMSTaskProcessor struct {
+	smsSender smsutils.SMSSender
+	cfg       *config.Config
+}
+
+func NewSMSTaskProcessor(
+	smsSender smsutils.SMSSender,
+	cfg *config.Config,
+) *SMSTaskProcessor {
+	return &SMSTaskProcessor{
+		smsSender: smsSender,
+		cfg:       cfg,
+	}
+}
+
+func (p *SMSTaskProcessor) HandleOTPSMS(ctx context.Context, t *asynq.Task) error {
+	payload := new(payload.OTPSMSPayload)
+	if err := json.Unmarshal(t.Payload(), payload); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s code: %s. Do not share this code with anyone.", p.cfg.App.AppName, payload.Code)
+
+	return p.smsSender.Send(ctx, payload.PhoneNumber, message)
+}
@@ -0,0 +1,13 @@
+package route
+
+import (
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/processor"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/tasks"
+
+	"github.com/hibiken/asynq"
+)
+
+func DataExportTaskRoute(mux *asynq.ServeMux, processor *processor.DataExportTaskProcessor) {
+	mux.HandleFunc(tasks.TypeDataExportBuild, processor.HandleBuild)
+	mux.HandleFunc(tasks.TypeDataExportCleanup, processor.HandleCleanup)
+}
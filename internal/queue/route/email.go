@@ -10,4 +10,11 @@ import (
 func EmailTaskRoute(mux *asynq.ServeMux, processor *processor.EmailTaskProcessor) {
 	mux.HandleFunc(tasks.TypeEmailVerification, processor.HandleVerificationEmail)
 	mux.HandleFunc(tasks.TypeEmailForgotPassword, processor.HandleForgotPasswordEmail)
+	mux.HandleFunc(tasks.TypeEmailMagicLink, processor.HandleMagicLinkEmail)
+	mux.HandleFunc(tasks.TypeEmailPasswordChange, processor.HandlePasswordChangedEmail)
+	mux.HandleFunc(tasks.TypeEmailChangeConfirm, processor.HandleEmailChangeConfirmation)
+	mux.HandleFunc(tasks.TypeEmailChangeNotice, processor.HandleEmailChangeNotice)
+	mux.HandleFunc(tasks.TypeEmailReactivation, processor.HandleReactivationEmail)
+	mux.HandleFunc(tasks.TypeEmailAccountDeletion, processor.HandleAccountDeletionEmail)
+	mux.HandleFunc(tasks.TypeEmailDataExport, processor.HandleDataExportEmail)
 }
@@ -0,0 +1,12 @@
+package route
+
+import (
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/processor"
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/tasks"
+
+	"github.com/hibiken/asynq"
+)
+
+func SMSTaskRoute(mux *asynq.ServeMux, processor *processor.SMSTaskProcessor) {
+	mux.HandleFunc(tasks.TypeSMSOTP, processor.HandleOTPSMS)
+}
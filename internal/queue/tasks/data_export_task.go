@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TypeDataExportBuild   = "data-export:build"
+	TypeDataExportCleanup = "data-export:cleanup"
+)
+
+type DataExportTask interface {
+	QueueBuild(ctx context.Context, payload *payload.DataExportPayload) error
+	QueueCleanup(ctx context.Context, payload *payload.DataExportCleanupPayload, processIn time.Duration) error
+}
+
+type dataExportTaskImpl struct {
+	client *asynq.Client
+}
+
+func NewDataExportTask(client *asynq.Client) *dataExportTaskImpl {
+	return &dataExportTaskImpl{
+		client: client,
+	}
+}
+
+func (t *dataExportTaskImpl) QueueBuild(ctx context.Context, payload *payload.DataExportPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeDataExportBuild, data, asynq.Timeout(2*time.Minute), asynq.MaxRetry(3))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+// QueueCleanup removes the stored archive once its download link has expired.
+func (t *dataExportTaskImpl) QueueCleanup(ctx context.Context, payload *payload.DataExportCleanupPayload, processIn time.Duration) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeDataExportCleanup, data, asynq.ProcessIn(processIn), asynq.Timeout(30*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
@@ -11,13 +11,27 @@ import (
 )
 
 const (
-	TypeEmailVerification   = "email:verification"
-	TypeEmailForgotPassword = "email:forgot-password"
+	TypeEmailVerification    = "email:verification"
+	TypeEmailForgotPassword  = "email:forgot-password"
+	TypeEmailMagicLink       = "email:magic-link"
+	TypeEmailPasswordChange  = "email:password-changed"
+	TypeEmailChangeConfirm   = "email:change-confirm"
+	TypeEmailChangeNotice    = "email:change-notice"
+	TypeEmailReactivation    = "email:reactivation"
+	TypeEmailAccountDeletion = "email:account-deletion"
+	TypeEmailDataExport      = "email:data-export"
 )
 
 type EmailTask interface {
 	QueueVerificationEmail(ctx context.Context, payload *payload.VerificationEmailPayload) error
 	QueueForgotPasswordEmail(ctx context.Context, payload *payload.ForgotPasswordEmailPayload) error
+	QueueMagicLinkEmail(ctx context.Context, payload *payload.MagicLinkEmailPayload) error
+	QueuePasswordChangedEmail(ctx context.Context, payload *payload.PasswordChangedEmailPayload) error
+	QueueEmailChangeConfirmation(ctx context.Context, payload *payload.EmailChangeConfirmationPayload) error
+	QueueEmailChangeNotice(ctx context.Context, payload *payload.EmailChangeNoticePayload) error
+	QueueReactivationEmail(ctx context.Context, payload *payload.ReactivationEmailPayload) error
+	QueueAccountDeletionEmail(ctx context.Context, payload *payload.AccountDeletionEmailPayload) error
+	QueueDataExportEmail(ctx context.Context, payload *payload.DataExportEmailPayload) error
 }
 
 type emailTaskImpl struct {
@@ -59,3 +73,109 @@ func (t *emailTaskImpl) QueueForgotPasswordEmail(ctx context.Context, payload *p
 
 	return err
 }
+
+func (t *emailTaskImpl) QueueMagicLinkEmail(ctx context.Context, payload *payload.MagicLinkEmailPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// a sign-in link is useless once it has expired, so retries stay well within its lifetime
+	task := asynq.NewTask(TypeEmailMagicLink, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(3))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+func (t *emailTaskImpl) QueuePasswordChangedEmail(ctx context.Context, payload *payload.PasswordChangedEmailPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeEmailPasswordChange, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+func (t *emailTaskImpl) QueueEmailChangeConfirmation(ctx context.Context, payload *payload.EmailChangeConfirmationPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeEmailChangeConfirm, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+func (t *emailTaskImpl) QueueEmailChangeNotice(ctx context.Context, payload *payload.EmailChangeNoticePayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeEmailChangeNotice, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+func (t *emailTaskImpl) QueueReactivationEmail(ctx context.Context, payload *payload.ReactivationEmailPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeEmailReactivation, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+func (t *emailTaskImpl) QueueAccountDeletionEmail(ctx context.Context, payload *payload.AccountDeletionEmailPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeEmailAccountDeletion, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
+
+func (t *emailTaskImpl) QueueDataExportEmail(ctx context.Context, payload *payload.DataExportEmailPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(TypeEmailDataExport, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(10))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
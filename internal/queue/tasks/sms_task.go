@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/queue/payload"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TypeSMSOTP = "sms:otp"
+)
+
+type SMSTask interface {
+	QueueOTPSMS(ctx context.Context, payload *payload.OTPSMSPayload) error
+}
+
+type smsTaskImpl struct {
+	client *asynq.Client
+}
+
+func NewSMSTask(client *asynq.Client) *smsTaskImpl {
+	return &smsTaskImpl{
+		client: client,
+	}
+}
+
+func (t *smsTaskImpl) QueueOTPSMS(ctx context.Context, payload *payload.OTPSMSPayload) error {
+	enqueueCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// the code expires within minutes, a late delivery is worse than none
+	task := asynq.NewTask(TypeSMSOTP, data, asynq.Timeout(5*time.Second), asynq.MaxRetry(3))
+	_, err = t.client.EnqueueContext(enqueueCtx, task)
+
+	return err
+}
@@ -2,6 +2,7 @@ package apperror
 
 import (
 	"errors"
+	"time"
 )
 
 const (
@@ -12,12 +13,14 @@ const (
 	TooManyRequestsErrorCode
 	ForbiddenAccessErrorCode
 	UnauthorizedErrorCode
+	LockedErrorCode
 )
 
 type AppError struct {
-	err  error
-	code int
-	msg  string
+	err        error
+	code       int
+	msg        string
+	retryAfter time.Duration
 }
 
 func NewAppError(err error, code int, msg string) *AppError {
@@ -39,6 +42,17 @@ func (e AppError) GetCode() int {
 	return e.code
 }
 
+// WithRetryAfter tells the client how long to wait before trying again,
+// the error handler exposes it as the Retry-After header.
+func (e *AppError) WithRetryAfter(retryAfter time.Duration) *AppError {
+	e.retryAfter = retryAfter
+	return e
+}
+
+func (e AppError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 func (e AppError) OriginalError() error {
 	var currErr AppError
 
@@ -2,22 +2,41 @@ package config
 
 import (
 	"log"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/constant"
 
 	"github.com/spf13/viper"
 )
 
+const minTOTPEncryptionKeyLength = 32
+
 type Config struct {
 	App             *AppConfig
 	HttpServer      *HttpServerConfig
 	Database        *DatabaseConfig
 	Jwt             *JwtConfig
+	Session         *SessionConfig
+	LoginProtection *LoginProtectionConfig
+	RateLimit       *RateLimitConfig
+	PasswordPolicy  *PasswordPolicyConfig
+	PasswordHash    *PasswordHashConfig
+	AccountDeletion *AccountDeletionConfig
+	DataExport      *DataExportConfig
+	Storage         *StorageConfig
+	OIDC            *OIDCConfig
+	TOTP            *TOTPConfig
+	WebAuthn        *WebAuthnConfig
 	SMTP            *SMTPConfig
+	SMS             *SMSConfig
 	Redis           *RedisConfig
 	ES              *ESConfig
 	Logger          *LoggerConfig
-	Google          *GoogleConfig
+	Oauth           *OauthConfig
 	RajaOngkir      *RajaOngkirConfig
 	URLClientConfig *URLClientConfig
 	LogstashConfig  *LogstashConfig
@@ -38,6 +57,9 @@ type HttpServerConfig struct {
 	GracePeriod          int    `mapstructure:"HTTP_SERVER_GRACE_PERIOD"`
 	MaxRequestPerSecond  int    `mapstructure:"HTTP_SERVER_MAX_REQUEST_PER_SECOND"`
 	RequestTimeoutPeriod int    `mapstructure:"HTTP_SERVER_REQUEST_TIMEOUT_PERIOD"`
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For is
+	// believed, left empty the client IP is always the remote address.
+	TrustedProxies []string `mapstructure:"HTTP_SERVER_TRUSTED_PROXIES"`
 }
 
 type DatabaseConfig struct {
@@ -60,6 +82,99 @@ type JwtConfig struct {
 	SecretKey       string   `mapstructure:"JWT_SECRET_KEY"`
 	TokenDuration   int      `mapstructure:"JWT_TOKEN_DURATION"`
 	RefreshDuration int      `mapstructure:"JWT_REFRESH_DURATION"`
+	KeysDir         string   `mapstructure:"JWT_KEYS_DIR"`
+	ActiveKID       string   `mapstructure:"JWT_ACTIVE_KID"`
+	// LegacySecretUntil keeps tokens signed with JWT_SECRET_KEY valid for a
+	// while after moving to the keyring, they are refused after it.
+	LegacySecretUntil     string    `mapstructure:"JWT_LEGACY_SECRET_UNTIL"`
+	LegacySecretUntilTime time.Time `mapstructure:"-"`
+}
+
+type SessionConfig struct {
+	IdleTimeout           int `mapstructure:"SESSION_IDLE_TIMEOUT"`
+	MaxAge                int `mapstructure:"SESSION_MAX_AGE"`
+	RememberMeIdleTimeout int `mapstructure:"SESSION_REMEMBER_ME_IDLE_TIMEOUT"`
+	RememberMeMaxAge      int `mapstructure:"SESSION_REMEMBER_ME_MAX_AGE"`
+}
+
+type LoginProtectionConfig struct {
+	MaxEmailFailures      int `mapstructure:"LOGIN_MAX_EMAIL_FAILURES"`
+	MaxIPFailures         int `mapstructure:"LOGIN_MAX_IP_FAILURES"`
+	DelayAfterFailures    int `mapstructure:"LOGIN_DELAY_AFTER_FAILURES"`
+	DelayBaseSeconds      int `mapstructure:"LOGIN_DELAY_BASE_SECONDS"`
+	FailureWindowMinute   int `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	LockoutDurationMinute int `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+}
+
+type RateLimitConfig struct {
+	LoginPerMinute   int `mapstructure:"RATE_LIMIT_LOGIN_PER_MINUTE"`
+	TokenPerMinute   int `mapstructure:"RATE_LIMIT_TOKEN_PER_MINUTE"`
+	SensitivePerHour int `mapstructure:"RATE_LIMIT_SENSITIVE_PER_HOUR"`
+	UserPerMinute    int `mapstructure:"RATE_LIMIT_USER_PER_MINUTE"`
+}
+
+type PasswordPolicyConfig struct {
+	HistorySize         int    `mapstructure:"PASSWORD_HISTORY_SIZE"`
+	BreachedDatasetPath string `mapstructure:"PASSWORD_BREACHED_DATASET_PATH"`
+	BreachedMinCount    int    `mapstructure:"PASSWORD_BREACHED_MIN_COUNT"`
+}
+
+type PasswordHashConfig struct {
+	Algorithm     string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	Argon2Memory  int    `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	Argon2Time    int    `mapstructure:"PASSWORD_ARGON2_TIME"`
+	Argon2Threads int    `mapstructure:"PASSWORD_ARGON2_THREADS"`
+	ScryptLogN    int    `mapstructure:"PASSWORD_SCRYPT_LN"`
+	ScryptR       int    `mapstructure:"PASSWORD_SCRYPT_R"`
+	ScryptP       int    `mapstructure:"PASSWORD_SCRYPT_P"`
+}
+
+type AccountDeletionConfig struct {
+	GracePeriodDays int `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD_DAYS"`
+	PurgeBatchSize  int `mapstructure:"ACCOUNT_DELETION_PURGE_BATCH_SIZE"`
+}
+
+type DataExportConfig struct {
+	LinkExpireHours int `mapstructure:"DATA_EXPORT_LINK_EXPIRE_HOURS"`
+}
+
+type StorageConfig struct {
+	// Backend is "s3" or "local", the latter only works while the api and
+	// the worker share one disk and is refused outside development.
+	Backend           string `mapstructure:"STORAGE_BACKEND"`
+	LocalDir          string `mapstructure:"STORAGE_LOCAL_DIR"`
+	S3Endpoint        string `mapstructure:"STORAGE_S3_ENDPOINT"`
+	S3Region          string `mapstructure:"STORAGE_S3_REGION"`
+	S3Bucket          string `mapstructure:"STORAGE_S3_BUCKET"`
+	S3AccessKeyID     string `mapstructure:"STORAGE_S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `mapstructure:"STORAGE_S3_SECRET_ACCESS_KEY"`
+}
+
+type OIDCConfig struct {
+	Issuer    string                 `mapstructure:"OIDC_ISSUER"`
+	LoginURL  string                 `mapstructure:"OIDC_LOGIN_URL"`
+	ClientIDs []string               `mapstructure:"OIDC_CLIENTS"`
+	Clients   map[string]*OIDCClient `mapstructure:"-"`
+}
+
+// OIDCClient is a relying party. A public client (a SPA or mobile app) cannot
+// keep a secret and has to use PKCE instead, every other client needs one.
+type OIDCClient struct {
+	ID           string
+	Secret       string
+	Public       bool
+	RedirectURIs []string
+}
+
+type TOTPConfig struct {
+	Issuer        string `mapstructure:"TOTP_ISSUER"`
+	EncryptionKey string `mapstructure:"TOTP_ENCRYPTION_KEY"`
+}
+
+type WebAuthnConfig struct {
+	RPID          string   `mapstructure:"WEBAUTHN_RP_ID"`
+	RPDisplayName string   `mapstructure:"WEBAUTHN_RP_DISPLAY_NAME"`
+	RPOrigins     []string `mapstructure:"WEBAUTHN_RP_ORIGINS"`
 }
 
 type SMTPConfig struct {
@@ -69,6 +184,16 @@ type SMTPConfig struct {
 	Port        int    `mapstructure:"SMTP_PORT"`
 }
 
+type SMSConfig struct {
+	// Sender is "webhook" or "log", the latter only logs the recipient and
+	// is refused outside development.
+	Sender          string `mapstructure:"SMS_SENDER"`
+	LogFilePath     string `mapstructure:"SMS_LOG_FILE_PATH"`
+	WebhookURL      string `mapstructure:"SMS_WEBHOOK_URL"`
+	WebhookToken    string `mapstructure:"SMS_WEBHOOK_TOKEN"`
+	OTPLoginEnabled bool   `mapstructure:"SMS_OTP_LOGIN_ENABLED"`
+}
+
 type RedisConfig struct {
 	Host              string `mapstructure:"REDIS_HOST"`
 	Port              int    `mapstructure:"REDIS_PORT"`
@@ -93,12 +218,28 @@ type URLClientConfig struct {
 	URLCientVerificationEmail string `mapstructure:"URL_CLIENT_VERIFICATION_EMAIL"`
 	URLClientForgotPassword   string `mapstructure:"URL_CLIENT_FORGOT_PASSWORD"`
 	URLClientOauthCallback    string `mapstructure:"URL_CLIENT_OAUTH_CALLBACK"`
+	URLClientChangeEmail      string `mapstructure:"URL_CLIENT_CHANGE_EMAIL"`
+	URLClientMagicLink        string `mapstructure:"URL_CLIENT_MAGIC_LINK"`
+	URLClientReactivation     string `mapstructure:"URL_CLIENT_REACTIVATION"`
+	URLClientDataExport       string `mapstructure:"URL_CLIENT_DATA_EXPORT"`
+}
+
+type OauthConfig struct {
+	ProviderNames []string                  `mapstructure:"OAUTH_PROVIDERS"`
+	Providers     map[string]*OauthProvider `mapstructure:"-"`
 }
 
-type GoogleConfig struct {
-	ClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
-	ClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
-	CallbackURL  string `mapstructure:"GOOGLE_CALLBACK_URL"`
+// OauthProvider is one login provider, its name is what appears in
+// /oauth/:provider and in the linked identities of users.
+type OauthProvider struct {
+	Name         string
+	Type         string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	CallbackURL  string
+	DiscoveryURL string
+	Tenant       string
 }
 
 type LogstashConfig struct {
@@ -125,11 +266,23 @@ func InitConfig() *Config {
 		Database:        initDbConfig(),
 		HttpServer:      initHttpServerConfig(),
 		Jwt:             initJwtConfig(),
+		Session:         initSessionConfig(),
+		LoginProtection: initLoginProtectionConfig(),
+		RateLimit:       initRateLimitConfig(),
+		PasswordPolicy:  initPasswordPolicyConfig(),
+		PasswordHash:    initPasswordHashConfig(),
+		AccountDeletion: initAccountDeletionConfig(),
+		DataExport:      initDataExportConfig(),
+		Storage:         initStorageConfig(),
+		OIDC:            initOIDCConfig(),
+		TOTP:            initTOTPConfig(),
+		WebAuthn:        initWebAuthnConfig(),
 		SMTP:            initSMTPConfig(),
+		SMS:             initSMSConfig(),
 		Redis:           initRedisConfig(),
 		ES:              initESConfig(),
 		Logger:          initLoggerConfig(),
-		Google:          initGoogleConfig(),
+		Oauth:           initOauthConfig(),
 		RajaOngkir:      initRajaOngkirConfig(),
 		URLClientConfig: initURLClientConfig(),
 		LogstashConfig:  initLogstashConfig(),
@@ -153,9 +306,167 @@ func initJwtConfig() *JwtConfig {
 		log.Fatalf("error mapping jwt config: %v", err)
 	}
 
+	// the signing key only changes when the config says so, every replica
+	// then signs with the same key during a rollout
+	if jwtConfig.KeysDir != "" && jwtConfig.ActiveKID == "" {
+		log.Fatalf("error mapping jwt config: JWT_ACTIVE_KID is required with JWT_KEYS_DIR")
+	}
+
+	if jwtConfig.LegacySecretUntil != "" {
+		legacySecretUntil, err := time.Parse(time.RFC3339, jwtConfig.LegacySecretUntil)
+		if err != nil {
+			log.Fatalf("error mapping jwt config: JWT_LEGACY_SECRET_UNTIL must be an RFC 3339 time: %v", err)
+		}
+		jwtConfig.LegacySecretUntilTime = legacySecretUntil
+	}
+
 	return jwtConfig
 }
 
+func initSessionConfig() *SessionConfig {
+	sessionConfig := &SessionConfig{}
+
+	if err := viper.Unmarshal(&sessionConfig); err != nil {
+		log.Fatalf("error mapping session config: %v", err)
+	}
+
+	return sessionConfig
+}
+
+func initLoginProtectionConfig() *LoginProtectionConfig {
+	loginProtectionConfig := &LoginProtectionConfig{}
+
+	if err := viper.Unmarshal(&loginProtectionConfig); err != nil {
+		log.Fatalf("error mapping login protection config: %v", err)
+	}
+
+	return loginProtectionConfig
+}
+
+func initRateLimitConfig() *RateLimitConfig {
+	rateLimitConfig := &RateLimitConfig{}
+
+	if err := viper.Unmarshal(&rateLimitConfig); err != nil {
+		log.Fatalf("error mapping rate limit config: %v", err)
+	}
+
+	return rateLimitConfig
+}
+
+func initPasswordPolicyConfig() *PasswordPolicyConfig {
+	passwordPolicyConfig := &PasswordPolicyConfig{}
+
+	if err := viper.Unmarshal(&passwordPolicyConfig); err != nil {
+		log.Fatalf("error mapping password policy config: %v", err)
+	}
+
+	return passwordPolicyConfig
+}
+
+func initPasswordHashConfig() *PasswordHashConfig {
+	passwordHashConfig := &PasswordHashConfig{}
+
+	if err := viper.Unmarshal(&passwordHashConfig); err != nil {
+		log.Fatalf("error mapping password hash config: %v", err)
+	}
+
+	// bad parameters would otherwise only surface on the first hash, argon2
+	// even panics on a time or thread count of 0
+	switch passwordHashConfig.Algorithm {
+	case "":
+		passwordHashConfig.Algorithm = "bcrypt"
+	case "bcrypt":
+	case "argon2id":
+		if passwordHashConfig.Argon2Time < 1 {
+			log.Fatalf("error mapping password hash config: PASSWORD_ARGON2_TIME must be at least 1")
+		}
+		if passwordHashConfig.Argon2Threads < 1 || passwordHashConfig.Argon2Threads > 255 {
+			log.Fatalf("error mapping password hash config: PASSWORD_ARGON2_THREADS must be between 1 and 255")
+		}
+		if passwordHashConfig.Argon2Memory < 8*passwordHashConfig.Argon2Threads || passwordHashConfig.Argon2Memory > math.MaxUint32 {
+			log.Fatalf("error mapping password hash config: PASSWORD_ARGON2_MEMORY must be between 8 KiB per thread and %d KiB", uint32(math.MaxUint32))
+		}
+	case "scrypt":
+		if passwordHashConfig.ScryptLogN < 1 || passwordHashConfig.ScryptLogN > 30 {
+			log.Fatalf("error mapping password hash config: PASSWORD_SCRYPT_LN must be between 1 and 30")
+		}
+		if passwordHashConfig.ScryptR < 1 || passwordHashConfig.ScryptP < 1 || passwordHashConfig.ScryptR*passwordHashConfig.ScryptP >= 1<<30 {
+			log.Fatalf("error mapping password hash config: PASSWORD_SCRYPT_R and PASSWORD_SCRYPT_P must be at least 1 and their product below 2^30")
+		}
+	default:
+		log.Fatalf("error mapping password hash config: unsupported PASSWORD_HASH_ALGORITHM %q", passwordHashConfig.Algorithm)
+	}
+
+	return passwordHashConfig
+}
+
+func initOIDCConfig() *OIDCConfig {
+	oidcConfig := &OIDCConfig{}
+
+	if err := viper.Unmarshal(&oidcConfig); err != nil {
+		log.Fatalf("error mapping oidc config: %v", err)
+	}
+
+	// every client declared in OIDC_CLIENTS is described by its own
+	// OIDC_CLIENT_<ID>_SECRET, OIDC_CLIENT_<ID>_PUBLIC and
+	// OIDC_CLIENT_<ID>_REDIRECT_URIS keys
+	oidcConfig.Clients = map[string]*OIDCClient{}
+	for _, clientID := range oidcConfig.ClientIDs {
+		clientID = strings.TrimSpace(clientID)
+		if clientID == "" {
+			continue
+		}
+
+		prefix := "OIDC_CLIENT_" + strings.ToUpper(strings.ReplaceAll(clientID, "-", "_"))
+		client := &OIDCClient{
+			ID:           clientID,
+			Secret:       viper.GetString(prefix + "_SECRET"),
+			Public:       viper.GetBool(prefix + "_PUBLIC"),
+			RedirectURIs: strings.Split(viper.GetString(prefix+"_REDIRECT_URIS"), ","),
+		}
+
+		if !client.Public && client.Secret == "" {
+			log.Fatalf("error mapping oidc config: %s_SECRET is empty, set a secret or mark the client public", prefix)
+		}
+
+		oidcConfig.Clients[clientID] = client
+	}
+
+	// relying parties verify id tokens with the public keys of the jwks, an
+	// HS256 id token could only be checked with JWT_SECRET_KEY itself
+	if len(oidcConfig.Clients) > 0 && viper.GetString("JWT_KEYS_DIR") == "" {
+		log.Fatalf("error mapping oidc config: OIDC_CLIENTS needs an asymmetric signing key, set JWT_KEYS_DIR and JWT_ACTIVE_KID")
+	}
+
+	return oidcConfig
+}
+
+func initTOTPConfig() *TOTPConfig {
+	totpConfig := &TOTPConfig{}
+
+	if err := viper.Unmarshal(&totpConfig); err != nil {
+		log.Fatalf("error mapping totp config: %v", err)
+	}
+
+	// the key is hashed into the AES key, a short one would still make a
+	// valid key that is easy to guess
+	if len(totpConfig.EncryptionKey) < minTOTPEncryptionKeyLength {
+		log.Fatalf("error mapping totp config: TOTP_ENCRYPTION_KEY must be at least %d characters", minTOTPEncryptionKeyLength)
+	}
+
+	return totpConfig
+}
+
+func initWebAuthnConfig() *WebAuthnConfig {
+	webAuthnConfig := &WebAuthnConfig{}
+
+	if err := viper.Unmarshal(&webAuthnConfig); err != nil {
+		log.Fatalf("error mapping webauthn config: %v", err)
+	}
+
+	return webAuthnConfig
+}
+
 func initSMTPConfig() *SMTPConfig {
 	smtpConfig := &SMTPConfig{}
 
@@ -166,6 +477,91 @@ func initSMTPConfig() *SMTPConfig {
 	return smtpConfig
 }
 
+func initAccountDeletionConfig() *AccountDeletionConfig {
+	accountDeletionConfig := &AccountDeletionConfig{}
+
+	if err := viper.Unmarshal(&accountDeletionConfig); err != nil {
+		log.Fatalf("error mapping account deletion config: %v", err)
+	}
+
+	if accountDeletionConfig.GracePeriodDays < 1 {
+		log.Fatalf("error mapping account deletion config: ACCOUNT_DELETION_GRACE_PERIOD_DAYS must be at least 1")
+	}
+
+	if accountDeletionConfig.PurgeBatchSize < 1 {
+		log.Fatalf("error mapping account deletion config: ACCOUNT_DELETION_PURGE_BATCH_SIZE must be at least 1")
+	}
+
+	return accountDeletionConfig
+}
+
+func initDataExportConfig() *DataExportConfig {
+	dataExportConfig := &DataExportConfig{}
+
+	if err := viper.Unmarshal(&dataExportConfig); err != nil {
+		log.Fatalf("error mapping data export config: %v", err)
+	}
+
+	if dataExportConfig.LinkExpireHours < 1 {
+		log.Fatalf("error mapping data export config: DATA_EXPORT_LINK_EXPIRE_HOURS must be at least 1")
+	}
+
+	return dataExportConfig
+}
+
+func initStorageConfig() *StorageConfig {
+	storageConfig := &StorageConfig{}
+
+	if err := viper.Unmarshal(&storageConfig); err != nil {
+		log.Fatalf("error mapping storage config: %v", err)
+	}
+
+	switch storageConfig.Backend {
+	case "local":
+		if viper.GetString("APP_ENVIRONMENT") == constant.RELEASE {
+			log.Fatalf("error mapping storage config: STORAGE_BACKEND local is not shared between the api and the worker, configure s3 for release")
+		}
+	case "s3":
+		if storageConfig.S3Endpoint == "" || storageConfig.S3Region == "" || storageConfig.S3Bucket == "" {
+			log.Fatalf("error mapping storage config: STORAGE_S3_ENDPOINT, STORAGE_S3_REGION and STORAGE_S3_BUCKET are required with STORAGE_BACKEND s3")
+		}
+		if storageConfig.S3AccessKeyID == "" || storageConfig.S3SecretAccessKey == "" {
+			log.Fatalf("error mapping storage config: STORAGE_S3_ACCESS_KEY_ID and STORAGE_S3_SECRET_ACCESS_KEY are required with STORAGE_BACKEND s3")
+		}
+	case "":
+		log.Fatalf("error mapping storage config: STORAGE_BACKEND is required")
+	default:
+		log.Fatalf("error mapping storage config: unsupported STORAGE_BACKEND %q", storageConfig.Backend)
+	}
+
+	return storageConfig
+}
+
+func initSMSConfig() *SMSConfig {
+	smsConfig := &SMSConfig{}
+
+	if err := viper.Unmarshal(&smsConfig); err != nil {
+		log.Fatalf("error mapping sms config: %v", err)
+	}
+
+	switch smsConfig.Sender {
+	case "log":
+		if viper.GetString("APP_ENVIRONMENT") == constant.RELEASE {
+			log.Fatalf("error mapping sms config: SMS_SENDER log never delivers codes, configure a real sender for release")
+		}
+	case "webhook":
+		if smsConfig.WebhookURL == "" {
+			log.Fatalf("error mapping sms config: SMS_WEBHOOK_URL is required with SMS_SENDER webhook")
+		}
+	case "":
+		log.Fatalf("error mapping sms config: SMS_SENDER is required")
+	default:
+		log.Fatalf("error mapping sms config: unsupported SMS_SENDER %q", smsConfig.Sender)
+	}
+
+	return smsConfig
+}
+
 func initRedisConfig() *RedisConfig {
 	redisConfig := &RedisConfig{}
 
@@ -176,14 +572,46 @@ func initRedisConfig() *RedisConfig {
 	return redisConfig
 }
 
-func initGoogleConfig() *GoogleConfig {
-	googleConfig := &GoogleConfig{}
+func initOauthConfig() *OauthConfig {
+	oauthConfig := &OauthConfig{}
 
-	if err := viper.Unmarshal(&googleConfig); err != nil {
-		log.Fatalf("error mapping redis config: %v", err)
+	if err := viper.Unmarshal(&oauthConfig); err != nil {
+		log.Fatalf("error mapping oauth config: %v", err)
+	}
+
+	// every provider declared in OAUTH_PROVIDERS is described by its own
+	// OAUTH_PROVIDER_<NAME>_* keys, the type defaults to the name
+	oauthConfig.Providers = map[string]*OauthProvider{}
+	names := make([]string, 0, len(oauthConfig.ProviderNames))
+	for _, name := range oauthConfig.ProviderNames {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OAUTH_PROVIDER_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		provider := &OauthProvider{
+			Name:         name,
+			Type:         viper.GetString(prefix + "_TYPE"),
+			ClientID:     viper.GetString(prefix + "_CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "_CLIENT_SECRET"),
+			CallbackURL:  viper.GetString(prefix + "_CALLBACK_URL"),
+			DiscoveryURL: viper.GetString(prefix + "_DISCOVERY_URL"),
+			Tenant:       viper.GetString(prefix + "_TENANT"),
+		}
+		if provider.Type == "" {
+			provider.Type = name
+		}
+		if scopes := viper.GetString(prefix + "_SCOPES"); scopes != "" {
+			provider.Scopes = strings.Split(scopes, ",")
+		}
+
+		oauthConfig.Providers[name] = provider
+		names = append(names, name)
 	}
+	oauthConfig.ProviderNames = names
 
-	return googleConfig
+	return oauthConfig
 }
 
 func initRajaOngkirConfig() *RajaOngkirConfig {
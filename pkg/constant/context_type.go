@@ -9,4 +9,6 @@ const (
 	ContextUserID    contextKey = "user_id"
 	ContextRole      contextKey = "role"
 	ContextJTI       contextKey = "jti"
+	ContextClientIP  contextKey = "client_ip"
+	ContextUserAgent contextKey = "user_agent"
 )
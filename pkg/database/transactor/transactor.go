@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/faisalyudiansah/auth-service-template/pkg/database"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
 )
 
 type Transactor interface {
@@ -30,14 +31,21 @@ func (t *transactorImpl) Atomic(ctx context.Context, fn func(context.Context) er
 		return err
 	}
 
-	txCtx := injectTx(ctx, tx)
+	hooks := &afterCommitHooks{}
+	txCtx := context.WithValue(injectTx(ctx, tx), afterCommitKey{}, hooks)
 
 	if err := fn(txCtx); err != nil {
 		_ = tx.Rollback(ctx)
 		return err
 	}
 
-	return tx.Commit(ctx)
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	hooks.run(ctx)
+
+	return nil
 }
 
 type TxKey struct{}
@@ -52,3 +60,35 @@ func ExtractTx(ctx context.Context) *database.Tx {
 	}
 	return nil
 }
+
+type afterCommitKey struct{}
+
+type afterCommitHooks struct {
+	fns []func(context.Context) error
+}
+
+// AfterCommit defers side effects that live outside the database, such as
+// revoking sessions in Redis, until the surrounding transaction has committed
+// so a rollback never leaves them behind. Without a transaction fn runs right
+// away and its error is returned. Once the data is committed there is nothing
+// left to undo, so a failing hook is only logged.
+func AfterCommit(ctx context.Context, fn func(context.Context) error) error {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks)
+	if !ok || ExtractTx(ctx) == nil {
+		return fn(ctx)
+	}
+
+	hooks.fns = append(hooks.fns, fn)
+
+	return nil
+}
+
+// run gets the context from before the transaction, so the hooks do not pick
+// up the committed transaction.
+func (h *afterCommitHooks) run(ctx context.Context) {
+	for _, fn := range h.fns {
+		if err := fn(ctx); err != nil {
+			logger.FromContext(ctx).Errorf("error running after commit hook: %v", err)
+		}
+	}
+}
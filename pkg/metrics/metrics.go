@@ -38,6 +38,19 @@ var (
 			Help: "Memory usage in bytes",
 		},
 	)
+	RefreshTokenReuse = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "auth_refresh_token_reuse_total",
+			Help: "Total number of rotated-out refresh tokens presented again",
+		},
+	)
+	LoginLockouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_lockouts_total",
+			Help: "Total number of temporary login lockouts",
+		},
+		[]string{"scope"},
+	)
 )
 
 func init() {
@@ -47,5 +60,7 @@ func init() {
 		TrafficLevels,
 		RequestLatency,
 		MemoryUsage,
+		RefreshTokenReuse,
+		LoginLockouts,
 	)
 }
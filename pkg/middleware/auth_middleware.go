@@ -3,12 +3,19 @@ package middleware
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
+	apperrorAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/apperror"
+	constantAuth "github.com/faisalyudiansah/auth-service-template/internal/auth/constant"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/entity"
 	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
 	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
 	"github.com/faisalyudiansah/auth-service-template/pkg/constant"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/jwtutils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/utils/redisutils"
 	sessioncookieutils "github.com/faisalyudiansah/auth-service-template/pkg/utils/sessionCookieUtils"
@@ -21,74 +28,272 @@ import (
 type AuthMiddleware struct {
 	redisUtil redisutils.RedisUtil
 	jwtUtil   jwtutils.JwtUtilInterface
+	cfg       *config.Config
 }
 
 func NewAuthMiddleware(
 	redisUtil redisutils.RedisUtil,
 	jwtUtil jwtutils.JwtUtilInterface,
+	cfg *config.Config,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
 		redisUtil: redisUtil,
 		jwtUtil:   jwtUtil,
+		cfg:       cfg,
 	}
 }
 
 func (m *AuthMiddleware) Authorization() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		sessionID, err := sessioncookieutils.GetSessionIDFromCookie(ctx)
-		if err != nil {
+		if err := m.authenticate(ctx); err != nil {
 			ctx.Error(err)
 			ctx.Abort()
 			return
 		}
 
-		if sessionID == uuid.Nil {
-			ctx.Error(apperror.NewForbiddenAccessError())
+		ctx.Next()
+	}
+}
+
+// ClientAuthorization admits the access tokens handed to OIDC relying parties,
+// and only when every scope in requiredScopes was granted to them. First-party
+// routes go through Authorization, which refuses these tokens.
+func (m *AuthMiddleware) ClientAuthorization(requiredScopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := m.authenticateClient(ctx, requiredScopes); err != nil {
+			ctx.Error(err)
 			ctx.Abort()
 			return
 		}
 
-		getSession := &entity.Session{}
+		ctx.Next()
+	}
+}
 
-		if err := m.redisUtil.GetWithScanJSON(
-			ctx,
-			utils.SessionKey(sessionID),
-			getSession,
-		); err != nil || getSession.UserID == uuid.Nil {
-			ctx.Error(apperror.NewForbiddenAccessError())
-			ctx.Abort()
-			return
+func (m *AuthMiddleware) OptionalAuthorization() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		_ = m.authenticate(ctx)
+
+		ctx.Next()
+	}
+}
+
+func (m *AuthMiddleware) authenticate(ctx *gin.Context) error {
+	if accessToken, ok := m.getBearerToken(ctx); ok {
+		return m.authenticateBearer(ctx, accessToken)
+	}
+
+	sessionID, err := sessioncookieutils.GetSessionIDFromCookie(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sessionID == uuid.Nil {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	getSession := &entity.Session{}
+
+	if err := m.redisUtil.GetWithScanJSON(
+		ctx,
+		utils.SessionKey(sessionID),
+		getSession,
+	); err != nil || getSession.UserID == uuid.Nil || getSession.ClientID != "" {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	if getSession.AccessToken == "" || len(getSession.AccessToken) == 0 {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	if ctx.FullPath() == "/auth/logout" {
+		m.injectCtxSessionID(ctx, sessionID)
+		return nil
+	}
+
+	claims, err := m.jwtUtil.Parse(getSession.AccessToken, jwtutils.TokenTypeAccess)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return apperror.NewExpiredTokenError()
 		}
+		return apperror.NewForbiddenAccessError()
+	}
 
-		if getSession.AccessToken == "" || len(getSession.AccessToken) == 0 {
-			ctx.Error(apperror.NewForbiddenAccessError())
-			ctx.Abort()
-			return
+	if err := m.checkActive(ctx, getSession.UserID); err != nil {
+		return err
+	}
+
+	m.injectCtx(claims, ctx, sessionID)
+	m.touchSession(ctx, getSession, true)
+
+	return nil
+}
+
+func (m *AuthMiddleware) authenticateBearer(ctx *gin.Context, accessToken string) error {
+	claims, err := m.jwtUtil.Parse(accessToken, jwtutils.TokenTypeAccess)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return apperror.NewExpiredTokenError()
 		}
+		return apperror.NewForbiddenAccessError()
+	}
 
-		if ctx.FullPath() == "/auth/logout" || ctx.FullPath() == "/auth/refresh" {
-			m.injectCtxSessionID(ctx, sessionID)
-			ctx.Next()
-			return
+	if claims.SessionID == uuid.Nil || claims.UserID == uuid.Nil {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	getSession := &entity.Session{}
+
+	if err := m.redisUtil.GetWithScanJSON(
+		ctx,
+		utils.SessionKey(claims.SessionID),
+		getSession,
+	); err != nil || getSession.UserID != claims.UserID || getSession.JTI != claims.ID || getSession.ClientID != "" {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	if err := m.checkActive(ctx, claims.UserID); err != nil {
+		return err
+	}
+
+	m.injectCtx(claims, ctx, claims.SessionID)
+	m.touchSession(ctx, getSession, false)
+
+	return nil
+}
+
+func (m *AuthMiddleware) authenticateClient(ctx *gin.Context, requiredScopes []string) error {
+	accessToken, ok := m.getBearerToken(ctx)
+	if !ok {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	claims, err := m.jwtUtil.Parse(accessToken, jwtutils.TokenTypeClientAccess)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return apperror.NewExpiredTokenError()
 		}
+		return apperror.NewForbiddenAccessError()
+	}
 
-		claims, err := m.jwtUtil.Parse(getSession.AccessToken)
-		if err != nil {
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				ctx.Error(apperror.NewExpiredTokenError())
-				ctx.Abort()
-				return
-			} else {
-				ctx.Error(apperror.NewForbiddenAccessError())
-				ctx.Abort()
-				return
-			}
+	if claims.SessionID == uuid.Nil || claims.UserID == uuid.Nil {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	getSession := &entity.Session{}
+
+	if err := m.redisUtil.GetWithScanJSON(
+		ctx,
+		utils.SessionKey(claims.SessionID),
+		getSession,
+	); err != nil ||
+		getSession.UserID != claims.UserID ||
+		getSession.JTI != claims.ID ||
+		getSession.ClientID == "" ||
+		!slices.Contains(claims.Audience, getSession.ClientID) {
+		return apperror.NewForbiddenAccessError()
+	}
+
+	grantedScopes := strings.Fields(getSession.Scope)
+	for _, scope := range requiredScopes {
+		if !slices.Contains(grantedScopes, scope) {
+			return apperrorAuth.NewInsufficientScopeError()
 		}
+	}
 
-		m.injectCtx(claims, ctx, sessionID)
+	if err := m.checkActive(ctx, claims.UserID); err != nil {
+		return err
+	}
 
-		ctx.Next()
+	m.injectCtx(claims, ctx, claims.SessionID)
+	m.touchSession(ctx, getSession, false)
+
+	return nil
+}
+
+// checkActive backs up the session revocation done when an account leaves
+// the active status, so it is turned away even by a session that slipped
+// through. The marker holds the status and expires with a suspension.
+func (m *AuthMiddleware) checkActive(ctx *gin.Context, userID uuid.UUID) error {
+	status, err := m.redisUtil.Get(ctx, utils.InactiveUserKey(userID))
+	if err != nil {
+		return apperror.NewServerError(err)
+	}
+
+	switch status {
+	case "":
+		return nil
+	case custom_type.UserStatusSuspended.String():
+		return apperrorAuth.NewAccountSuspendedError(nil)
+	case custom_type.UserStatusPendingDeletion.String():
+		return apperrorAuth.NewAccountPendingDeletionError()
+	default:
+		return apperrorAuth.NewAccountInactiveError()
+	}
+}
+
+// touchSession slides the idle timeout of an active session and records when
+// it was last seen, at most once per SessionTouchInterval, and re-issues the
+// cookie so its Max-Age follows the Redis TTL. Failures are only logged, the
+// request itself is already authenticated.
+func (m *AuthMiddleware) touchSession(ctx *gin.Context, session *entity.Session, fromCookie bool) {
+	currentTime := time.Now()
+
+	ttl := utils.SessionTTL(m.cfg.Session, session, currentTime)
+	if ttl <= 0 {
+		return
+	}
+
+	sessionKey := utils.SessionKey(session.SessionID)
+
+	currentTTL, err := m.redisUtil.TTL(ctx, sessionKey)
+	if err != nil {
+		logger.Log.Errorf("error reading session ttl: %v", err)
+		return
+	}
+
+	if ttl-currentTTL < constantAuth.SessionTouchInterval {
+		return
+	}
+
+	// the write is skipped when a refresh rotated the session meanwhile, it
+	// must not bring back the previous refresh token
+	session.LastSeenAt = uint64(currentTime.UnixMilli())
+	touched, err := m.redisUtil.CompareAndSetJSON(ctx, sessionKey, "refresh_jti", session.RefreshJTI, session, ttl)
+	if err != nil {
+		logger.Log.Errorf("error extending session ttl: %v", err)
+		return
+	}
+
+	if !touched {
+		return
 	}
+
+	indexKey := utils.UserSessionsKey(session.UserID)
+	if indexTTL, err := m.redisUtil.TTL(ctx, indexKey); err == nil && indexTTL < ttl {
+		if err := m.redisUtil.Expire(ctx, indexKey, ttl); err != nil {
+			logger.Log.Errorf("error extending user sessions ttl: %v", err)
+		}
+	}
+
+	if fromCookie {
+		utils.SetSessionCookie(ctx, session.SessionID, ttl)
+	}
+}
+
+func (m *AuthMiddleware) getBearerToken(ctx *gin.Context) (string, bool) {
+	authorization := ctx.GetHeader("Authorization")
+	if authorization == "" {
+		return "", false
+	}
+
+	tokenType, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(tokenType, constantAuth.BEARER_TOKEN_TYPE) {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
 }
 
 func (m *AuthMiddleware) ProtectedRoles(allowedRoles ...custom_type.Role) gin.HandlerFunc {
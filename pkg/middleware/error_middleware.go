@@ -28,6 +28,7 @@ var codeMap = map[int]int{
 	apperror.TooManyRequestsErrorCode: http.StatusTooManyRequests,
 	apperror.ForbiddenAccessErrorCode: http.StatusForbidden,
 	apperror.UnauthorizedErrorCode:    http.StatusUnauthorized,
+	apperror.LockedErrorCode:          http.StatusLocked,
 }
 
 var (
@@ -68,6 +69,9 @@ func ErrorHandler() gin.HandlerFunc {
 				if e.DisplayMessage() != orig {
 					originalErrorMsg = &orig
 				}
+				if retryAfter := e.RetryAfter(); retryAfter > 0 {
+					ctx.Header("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+				}
 				ctx.AbortWithStatusJSON(codeMap[e.GetCode()], dto.WebResponse[any]{
 					Message: e.DisplayMessage(),
 					Error:   originalErrorMsg,
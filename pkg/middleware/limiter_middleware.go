@@ -1,19 +1,112 @@
 package middleware
 
 import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/internal/auth/utils"
 	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+	"github.com/faisalyudiansah/auth-service-template/pkg/utils/ratelimitutils"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"github.com/google/uuid"
 )
 
-func RateLimiter(limiter *rate.Limiter) gin.HandlerFunc {
+type RateLimitMiddleware struct {
+	limiter  ratelimitutils.RateLimiter
+	policies map[string]ratelimitutils.Policy
+}
+
+func NewRateLimitMiddleware(
+	limiter ratelimitutils.RateLimiter,
+	cfg *config.Config,
+) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter: limiter,
+		policies: map[string]ratelimitutils.Policy{
+			ratelimitutils.PolicyGlobal: {
+				Name:  ratelimitutils.PolicyGlobal,
+				Limit: ratelimitutils.PerSecond(cfg.HttpServer.MaxRequestPerSecond),
+				KeyBy: ratelimitutils.KeyByIP,
+			},
+			ratelimitutils.PolicyLogin: {
+				Name:     ratelimitutils.PolicyLogin,
+				Limit:    ratelimitutils.PerMinute(cfg.RateLimit.LoginPerMinute),
+				KeyBy:    ratelimitutils.KeyByIP,
+				PerRoute: true,
+			},
+			ratelimitutils.PolicyToken: {
+				Name:     ratelimitutils.PolicyToken,
+				Limit:    ratelimitutils.PerMinute(cfg.RateLimit.TokenPerMinute),
+				KeyBy:    ratelimitutils.KeyByIP,
+				PerRoute: true,
+			},
+			ratelimitutils.PolicySensitive: {
+				Name:     ratelimitutils.PolicySensitive,
+				Limit:    ratelimitutils.PerHour(cfg.RateLimit.SensitivePerHour),
+				KeyBy:    ratelimitutils.KeyByIP,
+				PerRoute: true,
+			},
+			ratelimitutils.PolicyUser: {
+				Name:  ratelimitutils.PolicyUser,
+				Limit: ratelimitutils.PerMinute(cfg.RateLimit.UserPerMinute),
+				KeyBy: ratelimitutils.KeyByUser,
+			},
+		},
+	}
+}
+
+// RateLimiter applies the named policy. Policies keyed by user have to be
+// placed after Authorization, otherwise they fall back to the client IP.
+func (m *RateLimitMiddleware) RateLimiter(policyName string) gin.HandlerFunc {
+	policy, ok := m.policies[policyName]
+	if !ok {
+		panic(fmt.Sprintf("unknown rate limit policy %q", policyName))
+	}
+
 	return func(ctx *gin.Context) {
-		if !limiter.Allow() {
-			ctx.Error(apperror.NewLimitError())
+		route := ctx.Request.Method + " " + ctx.FullPath()
+
+		res, err := m.limiter.Allow(ctx, policy, route, m.subject(ctx, policy))
+		if err != nil {
+			// a Redis outage must not take the whole API down with it
+			logger.Log.Errorf("error checking rate limit %v: %v", policy.Name, err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Limit", strconv.Itoa(res.Limit.Rate))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+		ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", res.Limit.Rate, ceilSeconds(res.Limit.Period)))
+
+		if !res.Allowed {
+			ctx.Error(apperror.NewLimitError().WithRetryAfter(res.RetryAfter))
+			ctx.Abort()
 			return
 		}
 
 		ctx.Next()
 	}
 }
+
+func (m *RateLimitMiddleware) subject(ctx *gin.Context, policy ratelimitutils.Policy) string {
+	if policy.KeyBy == ratelimitutils.KeyByUser {
+		if userID := utils.GetValueUserIDFromContext(ctx); userID != uuid.Nil {
+			return userID.String()
+		}
+	}
+
+	return ctx.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
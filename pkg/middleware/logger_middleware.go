@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/faisalyudiansah/auth-service-template/pkg/apperror"
+	"github.com/faisalyudiansah/auth-service-template/pkg/constant"
 	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -21,9 +23,11 @@ func Logger() gin.HandlerFunc {
 
 		reqLogger := logger.Log.WithField("request_id", requestID)
 
-		ctx.Request = ctx.Request.WithContext(
-			logger.InjectToContext(ctx.Request.Context(), reqLogger),
-		)
+		reqCtx := logger.InjectToContext(ctx.Request.Context(), reqLogger)
+		reqCtx = context.WithValue(reqCtx, constant.ContextClientIP, ctx.ClientIP())
+		reqCtx = context.WithValue(reqCtx, constant.ContextUserAgent, ctx.Request.UserAgent())
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
 
 		ctx.Next()
 
@@ -0,0 +1,314 @@
+package breachutils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+)
+
+const (
+	rangePrefixLen = 5
+	// manifestName is the file a range directory has to hold next to its
+	// range files, it is touched once an update of the range files completes
+	// so a reload only has to look at a single modification time.
+	manifestName = "MANIFEST"
+)
+
+// rangeExts are the extensions range files are looked up with.
+var rangeExts = []string{"", ".txt"}
+
+type BreachChecker interface {
+	IsBreached(password string) bool
+	Reload() error
+}
+
+// breachChecker screens passwords against a local copy of the HIBP Pwned
+// Passwords corpus. DatasetPath is either a directory of range files, named
+// by their 5 character hash prefix and holding SUFFIX:COUNT lines, or a single
+// file of HASH:COUNT lines, both sorted by hash. The corpus stays on disk,
+// every lookup binary searches the range file of its prefix, or the single
+// file, so memory use does not grow with the dataset.
+type breachChecker struct {
+	mu              sync.RWMutex
+	cfg             *config.PasswordPolicyConfig
+	dataset         *dataset
+	manifestModTime time.Time
+}
+
+type dataset struct {
+	path  string
+	isDir bool
+	// rangeExt is the extension the range files of a directory carry.
+	rangeExt string
+}
+
+func NewBreachChecker(cfg *config.PasswordPolicyConfig) *breachChecker {
+	checker := &breachChecker{
+		cfg: cfg,
+	}
+
+	if cfg.BreachedDatasetPath == "" {
+		logger.Log.Warn("breached password screening is disabled, PASSWORD_BREACHED_DATASET_PATH is not set")
+		return checker
+	}
+
+	if err := checker.load(); err != nil {
+		log.Fatalf("error loading breached password dataset: %v", err)
+	}
+
+	return checker
+}
+
+// IsBreached fails open, a dataset that cannot be read is logged and the
+// password is let through.
+func (c *breachChecker) IsBreached(password string) bool {
+	c.mu.RLock()
+	ds := c.dataset
+	c.mu.RUnlock()
+
+	if ds == nil {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	path, target := ds.path, hash
+	if ds.isDir {
+		path = filepath.Join(ds.path, hash[:rangePrefixLen]+ds.rangeExt)
+		target = hash[rangePrefixLen:]
+	}
+
+	count, found, err := searchFile(path, target)
+	if err != nil {
+		// a trimmed corpus may leave out ranges without any entry
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Log.Errorf("error searching breached password dataset: %v", err)
+		}
+		return false
+	}
+
+	if !found {
+		return false
+	}
+
+	return count < 0 || count >= c.cfg.BreachedMinCount
+}
+
+// Reload checks the dataset again once its manifest, or the single dataset
+// file, changed on disk. The previous one stays in use when the new one
+// cannot be read.
+func (c *breachChecker) Reload() error {
+	c.mu.RLock()
+	ds := c.dataset
+	loadedModTime := c.manifestModTime
+	c.mu.RUnlock()
+
+	if ds == nil {
+		return nil
+	}
+
+	modTime, err := manifestModTime(ds)
+	if err != nil {
+		return err
+	}
+
+	if modTime.Equal(loadedModTime) {
+		return nil
+	}
+
+	return c.load()
+}
+
+func (c *breachChecker) load() error {
+	path := c.cfg.BreachedDatasetPath
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	ds := &dataset{
+		path:  path,
+		isDir: info.IsDir(),
+	}
+
+	if ds.isDir {
+		if ds.rangeExt, err = detectRangeExt(path); err != nil {
+			return err
+		}
+	}
+
+	modTime, err := manifestModTime(ds)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dataset = ds
+	c.manifestModTime = modTime
+
+	return nil
+}
+
+// detectRangeExt finds the extension of the range files by the first range,
+// which every complete corpus has.
+func detectRangeExt(dir string) (string, error) {
+	firstRange := strings.Repeat("0", rangePrefixLen)
+
+	for _, ext := range rangeExts {
+		if _, err := os.Stat(filepath.Join(dir, firstRange+ext)); err == nil {
+			return ext, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("%s: range file %s not found", dir, firstRange)
+}
+
+func manifestModTime(ds *dataset) (time.Time, error) {
+	path := ds.path
+	if ds.isDir {
+		path = filepath.Join(ds.path, manifestName)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// searchFile binary searches a file of HASH:COUNT lines sorted by hash for
+// target. The count of a matching line is -1 when the line carries none.
+func searchFile(path, target string) (int, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+	size := info.Size()
+
+	// lo always points at the start of a line, a match can only start
+	// within [lo, hi)
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		start, err := nextLineStart(file, size, mid)
+		if err != nil {
+			return 0, false, err
+		}
+
+		// no line starts in the upper half, the few lines left are scanned
+		if start >= hi {
+			break
+		}
+
+		line, err := readLine(file, size, start)
+		if err != nil {
+			return 0, false, err
+		}
+
+		hash, count, err := parseLine(path, line)
+		if err != nil {
+			return 0, false, err
+		}
+
+		switch strings.Compare(hash, target) {
+		case 0:
+			return count, true, nil
+		case -1:
+			lo = start + int64(len(line))
+		default:
+			hi = start
+		}
+	}
+
+	for lo < hi {
+		line, err := readLine(file, size, lo)
+		if err != nil {
+			return 0, false, err
+		}
+		if line == "" {
+			break
+		}
+
+		hash, count, err := parseLine(path, line)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if hash == target {
+			return count, true, nil
+		}
+
+		lo += int64(len(line))
+	}
+
+	return 0, false, nil
+}
+
+// nextLineStart is the offset of the first line starting at or after pos.
+func nextLineStart(file *os.File, size, pos int64) (int64, error) {
+	if pos == 0 {
+		return 0, nil
+	}
+
+	rest, err := readLine(file, size, pos-1)
+	if err != nil {
+		return 0, err
+	}
+
+	return pos - 1 + int64(len(rest)), nil
+}
+
+// readLine reads from pos up to and including the next newline, the last
+// line of a file may come without one.
+func readLine(file *os.File, size, pos int64) (string, error) {
+	line, err := bufio.NewReader(io.NewSectionReader(file, pos, size-pos)).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return line, nil
+}
+
+func parseLine(path, line string) (string, int, error) {
+	hash, countValue, hasCount := strings.Cut(strings.TrimSpace(line), ":")
+	hash = strings.ToUpper(hash)
+
+	if !hasCount {
+		return hash, -1, nil
+	}
+
+	count, err := strconv.Atoi(countValue)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: invalid count of %s: %w", path, hash, err)
+	}
+
+	return hash, count, nil
+}
@@ -2,7 +2,12 @@ package cloudinaryutils
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
@@ -10,8 +15,11 @@ import (
 
 type CloudinaryUtil interface {
 	UploadImage(ctx context.Context, image any, uploadParams uploader.UploadParams) (string, error)
+	DeleteImage(ctx context.Context, imageURL string) error
 }
 
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
 type cloudinaryUtil struct {
 	cld *cloudinary.Cloudinary
 }
@@ -46,3 +54,53 @@ func (c *cloudinaryUtil) UploadImage(ctx context.Context, image any, uploadParam
 
 	return imgUrl, nil
 }
+
+// DeleteImage removes an image uploaded to this cloud, URLs pointing anywhere
+// else are left alone.
+func (c *cloudinaryUtil) DeleteImage(ctx context.Context, imageURL string) error {
+	publicID, ok := c.publicIDFromURL(imageURL)
+	if !ok {
+		return nil
+	}
+
+	res, err := c.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicID})
+	if err != nil {
+		return err
+	}
+
+	if res.Error.Message != "" {
+		return errors.New(res.Error.Message)
+	}
+
+	return nil
+}
+
+// publicIDFromURL reads the public id out of a delivery URL such as
+// https://res.cloudinary.com/<cloud>/image/upload/v1712/<public_id>.jpg
+func (c *cloudinaryUtil) publicIDFromURL(imageURL string) (string, bool) {
+	u, err := url.Parse(imageURL)
+	if err != nil || u.Host != "res.cloudinary.com" {
+		return "", false
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 4 || segments[0] != c.cld.Config.Cloud.CloudName || segments[1] != "image" || segments[2] != "upload" {
+		return "", false
+	}
+
+	segments = segments[3:]
+	for i, segment := range segments {
+		if versionSegment.MatchString(segment) {
+			segments = segments[i+1:]
+			break
+		}
+	}
+
+	if len(segments) == 0 {
+		return "", false
+	}
+
+	publicID := strings.Join(segments, "/")
+
+	return strings.TrimSuffix(publicID, path.Ext(publicID)), true
+}
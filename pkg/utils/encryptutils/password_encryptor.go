@@ -7,6 +7,7 @@ import (
 type PasswordEncryptor interface {
 	Hash(password string) (string, error)
 	Check(password, hash string) bool
+	NeedsRehash(hash string) bool
 }
 
 type bcryptPasswordEncryptor struct {
@@ -31,3 +32,8 @@ func (e *bcryptPasswordEncryptor) Check(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+func (e *bcryptPasswordEncryptor) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != e.cost
+}
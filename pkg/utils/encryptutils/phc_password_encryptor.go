@@ -0,0 +1,256 @@
+package encryptutils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+	PasswordHashScrypt   = "scrypt"
+)
+
+const (
+	phcSaltLen = 16
+	phcKeyLen  = 32
+)
+
+// phcPasswordEncryptor hashes with the configured algorithm and verifies any
+// hash it understands: bcrypt ($2a$/$2b$/$2y$) as well as argon2id and scrypt
+// PHC strings. Hashes made with another algorithm or weaker parameters are
+// reported by NeedsRehash so they can be upgraded on the next login.
+type phcPasswordEncryptor struct {
+	algorithm string
+	bcrypt    *bcryptPasswordEncryptor
+	argon2    argon2Params
+	scrypt    scryptParams
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	keyLen  uint32
+}
+
+type scryptParams struct {
+	logN   int
+	r      int
+	p      int
+	keyLen int
+}
+
+// NewPHCPasswordEncryptor expects cfg to be validated already, the config
+// package refuses to start with an unknown algorithm or unusable parameters.
+func NewPHCPasswordEncryptor(cfg *config.PasswordHashConfig, bcryptCost int) *phcPasswordEncryptor {
+	return &phcPasswordEncryptor{
+		algorithm: cfg.Algorithm,
+		bcrypt:    NewBcryptPasswordEncryptor(bcryptCost),
+		argon2: argon2Params{
+			memory:  uint32(cfg.Argon2Memory),
+			time:    uint32(cfg.Argon2Time),
+			threads: uint8(cfg.Argon2Threads),
+			keyLen:  phcKeyLen,
+		},
+		scrypt: scryptParams{
+			logN:   cfg.ScryptLogN,
+			r:      cfg.ScryptR,
+			p:      cfg.ScryptP,
+			keyLen: phcKeyLen,
+		},
+	}
+}
+
+func (e *phcPasswordEncryptor) Hash(password string) (string, error) {
+	switch e.algorithm {
+	case PasswordHashBcrypt:
+		return e.bcrypt.Hash(password)
+	case PasswordHashArgon2id:
+		return e.hashArgon2id(password)
+	case PasswordHashScrypt:
+		return e.hashScrypt(password)
+	}
+
+	return "", fmt.Errorf("unsupported password hash algorithm %q", e.algorithm)
+}
+
+func (e *phcPasswordEncryptor) Check(password, hash string) bool {
+	switch hashAlgorithm(hash) {
+	case PasswordHashBcrypt:
+		return e.bcrypt.Check(password, hash)
+	case PasswordHashArgon2id:
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+		derived := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLen)
+		return subtle.ConstantTimeCompare(derived, key) == 1
+	case PasswordHashScrypt:
+		params, salt, key, err := decodeScrypt(hash)
+		if err != nil {
+			return false
+		}
+		derived, err := scrypt.Key([]byte(password), salt, 1<<params.logN, params.r, params.p, params.keyLen)
+		if err != nil {
+			return false
+		}
+		return subtle.ConstantTimeCompare(derived, key) == 1
+	}
+
+	return false
+}
+
+func (e *phcPasswordEncryptor) NeedsRehash(hash string) bool {
+	if hashAlgorithm(hash) != e.algorithm {
+		return true
+	}
+
+	switch e.algorithm {
+	case PasswordHashBcrypt:
+		return e.bcrypt.NeedsRehash(hash)
+	case PasswordHashArgon2id:
+		params, _, _, err := decodeArgon2id(hash)
+		return err != nil || params != e.argon2
+	case PasswordHashScrypt:
+		params, _, _, err := decodeScrypt(hash)
+		return err != nil || params != e.scrypt
+	}
+
+	return true
+}
+
+func (e *phcPasswordEncryptor) hashArgon2id(password string) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+
+	p := e.argon2
+	key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, p.keyLen)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (e *phcPasswordEncryptor) hashScrypt(password string) (string, error) {
+	salt, err := randomSalt()
+	if err != nil {
+		return "", err
+	}
+
+	p := e.scrypt
+	key, err := scrypt.Key([]byte(password), salt, 1<<p.logN, p.r, p.p, p.keyLen)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		p.logN, p.r, p.p,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func hashAlgorithm(hash string) string {
+	switch {
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return PasswordHashBcrypt
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return PasswordHashArgon2id
+	case strings.HasPrefix(hash, "$scrypt$"):
+		return PasswordHashScrypt
+	}
+
+	return ""
+}
+
+// decodeArgon2id parses $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, err
+	}
+	if params.time == 0 || params.threads == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters")
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[4], parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.keyLen = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// decodeScrypt parses $scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<key>.
+func decodeScrypt(hash string) (scryptParams, []byte, []byte, error) {
+	var params scryptParams
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 {
+		return params, nil, nil, fmt.Errorf("invalid scrypt hash")
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.logN, &params.r, &params.p); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[3], parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.keyLen = len(key)
+
+	return params, salt, key, nil
+}
+
+func decodeSaltAndKey(encodedSalt, encodedKey string) ([]byte, []byte, error) {
+	salt, err := base64.RawStdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return salt, key, nil
+}
+
+func randomSalt() ([]byte, error) {
+	salt := make([]byte, phcSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
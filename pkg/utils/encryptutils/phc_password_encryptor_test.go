@@ -0,0 +1,172 @@
+package encryptutils
+
+import (
+	"testing"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testHashConfig keeps the parameters low, the tests are about the format
+// and not the cost.
+func testHashConfig(algorithm string) *config.PasswordHashConfig {
+	return &config.PasswordHashConfig{
+		Algorithm:     algorithm,
+		Argon2Memory:  1024,
+		Argon2Time:    1,
+		Argon2Threads: 1,
+		ScryptLogN:    10,
+		ScryptR:       8,
+		ScryptP:       1,
+	}
+}
+
+func TestPHCPasswordEncryptor_HashAndCheck(t *testing.T) {
+	for _, algorithm := range []string{PasswordHashBcrypt, PasswordHashArgon2id, PasswordHashScrypt} {
+		t.Run(algorithm, func(t *testing.T) {
+			encryptor := NewPHCPasswordEncryptor(testHashConfig(algorithm), bcrypt.MinCost)
+
+			hash, err := encryptor.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+
+			if hashAlgorithm(hash) != algorithm {
+				t.Fatalf("Hash() = %q, want a %s hash", hash, algorithm)
+			}
+
+			if !encryptor.Check("correct horse", hash) {
+				t.Fatalf("Check() rejected the right password")
+			}
+
+			if encryptor.Check("wrong horse", hash) {
+				t.Fatalf("Check() accepted a wrong password")
+			}
+
+			if encryptor.NeedsRehash(hash) {
+				t.Fatalf("NeedsRehash() = true for a hash with the current parameters")
+			}
+
+			again, err := encryptor.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if again == hash {
+				t.Fatalf("Hash() reused its salt")
+			}
+		})
+	}
+}
+
+func TestPHCPasswordEncryptor_CheckAcrossAlgorithms(t *testing.T) {
+	hashes := map[string]string{}
+	for _, algorithm := range []string{PasswordHashBcrypt, PasswordHashArgon2id, PasswordHashScrypt} {
+		hash, err := NewPHCPasswordEncryptor(testHashConfig(algorithm), bcrypt.MinCost).Hash("correct horse")
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		hashes[algorithm] = hash
+	}
+
+	encryptor := NewPHCPasswordEncryptor(testHashConfig(PasswordHashArgon2id), bcrypt.MinCost)
+
+	for algorithm, hash := range hashes {
+		if !encryptor.Check("correct horse", hash) {
+			t.Errorf("Check() rejected a %s hash", algorithm)
+		}
+	}
+}
+
+func TestPHCPasswordEncryptor_NeedsRehash(t *testing.T) {
+	hashWith := func(t *testing.T, cfg *config.PasswordHashConfig, bcryptCost int) string {
+		hash, err := NewPHCPasswordEncryptor(cfg, bcryptCost).Hash("correct horse")
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		return hash
+	}
+
+	weakArgon2 := testHashConfig(PasswordHashArgon2id)
+	weakArgon2.Argon2Memory = 512
+
+	weakScrypt := testHashConfig(PasswordHashScrypt)
+	weakScrypt.ScryptLogN = 9
+
+	tests := []struct {
+		name       string
+		current    *config.PasswordHashConfig
+		hash       func(t *testing.T) string
+		wantRehash bool
+	}{
+		{
+			name:       "bcrypt to argon2id",
+			current:    testHashConfig(PasswordHashArgon2id),
+			hash:       func(t *testing.T) string { return hashWith(t, testHashConfig(PasswordHashBcrypt), bcrypt.MinCost) },
+			wantRehash: true,
+		},
+		{
+			name:       "argon2id to scrypt",
+			current:    testHashConfig(PasswordHashScrypt),
+			hash:       func(t *testing.T) string { return hashWith(t, testHashConfig(PasswordHashArgon2id), bcrypt.MinCost) },
+			wantRehash: true,
+		},
+		{
+			name:       "weaker argon2id memory",
+			current:    testHashConfig(PasswordHashArgon2id),
+			hash:       func(t *testing.T) string { return hashWith(t, weakArgon2, bcrypt.MinCost) },
+			wantRehash: true,
+		},
+		{
+			name:       "weaker scrypt cost",
+			current:    testHashConfig(PasswordHashScrypt),
+			hash:       func(t *testing.T) string { return hashWith(t, weakScrypt, bcrypt.MinCost) },
+			wantRehash: true,
+		},
+		{
+			name:       "other bcrypt cost",
+			current:    testHashConfig(PasswordHashBcrypt),
+			hash:       func(t *testing.T) string { return hashWith(t, testHashConfig(PasswordHashBcrypt), bcrypt.MinCost+1) },
+			wantRehash: true,
+		},
+		{
+			name:    "current argon2id",
+			current: testHashConfig(PasswordHashArgon2id),
+			hash:    func(t *testing.T) string { return hashWith(t, testHashConfig(PasswordHashArgon2id), bcrypt.MinCost) },
+		},
+		{
+			name:       "malformed hash",
+			current:    testHashConfig(PasswordHashArgon2id),
+			hash:       func(t *testing.T) string { return "$argon2id$v=19$broken" },
+			wantRehash: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encryptor := NewPHCPasswordEncryptor(tt.current, bcrypt.MinCost)
+
+			if got := encryptor.NeedsRehash(tt.hash(t)); got != tt.wantRehash {
+				t.Fatalf("NeedsRehash() = %v, want %v", got, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func TestPHCPasswordEncryptor_CheckRejectsMalformedHashes(t *testing.T) {
+	encryptor := NewPHCPasswordEncryptor(testHashConfig(PasswordHashArgon2id), bcrypt.MinCost)
+
+	for _, hash := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$!!!",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=10,r=8$c2FsdA$a2V5",
+		"$2a$04$short",
+	} {
+		if encryptor.Check("correct horse", hash) {
+			t.Errorf("Check() accepted %q", hash)
+		}
+	}
+}
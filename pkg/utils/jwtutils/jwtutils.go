@@ -2,6 +2,7 @@ package jwtutils
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	custom_type "github.com/faisalyudiansah/auth-service-template/internal/auth/entity/type"
@@ -12,33 +13,72 @@ import (
 )
 
 type JwtUtilInterface interface {
-	Sign(userID uuid.UUID, role custom_type.Role, jti string, currentTime time.Time) (string, error)
-	SignRefresh(currentTime time.Time) (string, error)
-	Parse(tokenString string) (*JWTClaims, error)
+	Sign(userID uuid.UUID, role custom_type.Role, sessionID uuid.UUID, jti string, currentTime time.Time) (string, error)
+	SignRefresh(sessionID uuid.UUID, jti string, currentTime time.Time) (string, error)
+	SignClientAccess(userID, sessionID uuid.UUID, jti, clientID, scope string, currentTime time.Time) (string, error)
+	Parse(tokenString, tokenType string) (*JWTClaims, error)
+	SignIDToken(claims *IDTokenClaims) (string, error)
+	SigningAlg() string
+	JWKS() *JWKSet
+	ReloadKeys() error
 }
 
 type jwtUtil struct {
 	jwtConfig *config.JwtConfig
+	keyring   *keyring
 }
 
 func NewJwtUtil(jwtConfig *config.JwtConfig) *jwtUtil {
+	keyring := newKeyring(jwtConfig.KeysDir, jwtConfig.ActiveKID, jwtConfig.AllowedAlgs)
+	if err := keyring.Load(); err != nil {
+		log.Fatalf("error loading jwt signing keys: %v", err)
+	}
+
 	return &jwtUtil{
 		jwtConfig: jwtConfig,
+		keyring:   keyring,
 	}
 }
 
+// Every token names what it is for, so a refresh token is never accepted as
+// an access token or the other way around. Access tokens handed to OIDC
+// relying parties have their own type and only open scope-gated endpoints.
+const (
+	TokenTypeAccess       = "access"
+	TokenTypeRefresh      = "refresh"
+	TokenTypeClientAccess = "client_access"
+)
+
+var ErrTokenTypeMismatch = errors.New("token type mismatch")
+
 type JWTClaims struct {
 	jwt.RegisteredClaims
-	UserID  uuid.UUID        `json:"user_id"`
-	Role    custom_type.Role `json:"role"`
-	LoginAt uint64           `json:"login_at"`
+	TokenType string           `json:"typ"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Role      custom_type.Role `json:"role"`
+	SessionID uuid.UUID        `json:"sid"`
+	LoginAt   uint64           `json:"login_at"`
+	Scope     string           `json:"scope,omitempty"`
+}
+
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthTime      int64  `json:"auth_time,omitempty"`
+	Nonce         string `json:"nonce,omitempty"`
+	SessionID     string `json:"sid,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
 }
 
-func (h *jwtUtil) Sign(userID uuid.UUID, role custom_type.Role, jti string, currentTime time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
-		UserID:  userID,
-		Role:    role,
-		LoginAt: uint64(currentTime.UnixMilli()),
+func (h *jwtUtil) Sign(userID uuid.UUID, role custom_type.Role, sessionID uuid.UUID, jti string, currentTime time.Time) (string, error) {
+	return h.signClaims(JWTClaims{
+		TokenType: TokenTypeAccess,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		LoginAt:   uint64(currentTime.UnixMilli()),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        jti,
 			IssuedAt:  jwt.NewNumericDate(currentTime),
@@ -46,34 +86,65 @@ func (h *jwtUtil) Sign(userID uuid.UUID, role custom_type.Role, jti string, curr
 			Issuer:    h.jwtConfig.Issuer,
 		},
 	})
-
-	s, err := token.SignedString([]byte(h.jwtConfig.SecretKey))
-	if err != nil {
-		return "", err
-	}
-
-	return s, nil
 }
 
-func (h *jwtUtil) SignRefresh(currentTime time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
+func (h *jwtUtil) SignRefresh(sessionID uuid.UUID, jti string, currentTime time.Time) (string, error) {
+	return h.signClaims(JWTClaims{
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ID:        uuid.NewString(),
+			ID:        jti,
 			IssuedAt:  jwt.NewNumericDate(currentTime),
 			ExpiresAt: jwt.NewNumericDate(currentTime.Add(time.Duration(h.jwtConfig.RefreshDuration) * time.Minute)),
 			Issuer:    h.jwtConfig.Issuer,
 		},
 	})
+}
 
-	s, err := token.SignedString([]byte(h.jwtConfig.SecretKey))
-	if err != nil {
-		return "", err
+func (h *jwtUtil) SignClientAccess(userID, sessionID uuid.UUID, jti, clientID, scope string, currentTime time.Time) (string, error) {
+	return h.signClaims(JWTClaims{
+		TokenType: TokenTypeClientAccess,
+		UserID:    userID,
+		SessionID: sessionID,
+		LoginAt:   uint64(currentTime.UnixMilli()),
+		Scope:     scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{clientID},
+			IssuedAt:  jwt.NewNumericDate(currentTime),
+			ExpiresAt: jwt.NewNumericDate(currentTime.Add(time.Duration(h.jwtConfig.TokenDuration) * time.Minute)),
+			Issuer:    h.jwtConfig.Issuer,
+		},
+	})
+}
+
+func (h *jwtUtil) SignIDToken(claims *IDTokenClaims) (string, error) {
+	return h.signClaims(claims)
+}
+
+func (h *jwtUtil) SigningAlg() string {
+	if activeKey := h.keyring.Active(); activeKey != nil {
+		return activeKey.Method.Alg()
 	}
 
-	return s, nil
+	return jwt.SigningMethodHS256.Alg()
 }
 
-func (h *jwtUtil) Parse(tokenString string) (*JWTClaims, error) {
+func (h *jwtUtil) signClaims(claims jwt.Claims) (string, error) {
+	activeKey := h.keyring.Active()
+	if activeKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(h.jwtConfig.SecretKey))
+	}
+
+	token := jwt.NewWithClaims(activeKey.Method, claims)
+	token.Header["kid"] = activeKey.KID
+
+	return token.SignedString(activeKey.PrivateKey)
+}
+
+func (h *jwtUtil) Parse(tokenString, tokenType string) (*JWTClaims, error) {
 	parser := jwt.NewParser(
 		jwt.WithValidMethods(h.jwtConfig.AllowedAlgs),
 		jwt.WithIssuer(h.jwtConfig.Issuer),
@@ -81,16 +152,23 @@ func (h *jwtUtil) Parse(tokenString string) (*JWTClaims, error) {
 		jwt.WithExpirationRequired(),
 	)
 
-	return h.parseClaims(parser, tokenString)
+	claims, err := h.parseClaims(parser, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, ErrTokenTypeMismatch
+	}
+
+	return claims, nil
 }
 
 func (h *jwtUtil) parseClaims(parser *jwt.Parser, tokenString string) (*JWTClaims, error) {
 	token, err := parser.ParseWithClaims(
 		tokenString,
 		&JWTClaims{},
-		func(t *jwt.Token) (any, error) {
-			return []byte(h.jwtConfig.SecretKey), nil
-		},
+		h.verificationKey,
 	)
 
 	if err != nil {
@@ -104,3 +182,47 @@ func (h *jwtUtil) parseClaims(parser *jwt.Parser, tokenString string) (*JWTClaim
 
 	return claims, nil
 }
+
+func (h *jwtUtil) verificationKey(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok && h.acceptsSecretKey(time.Now()) {
+			return []byte(h.jwtConfig.SecretKey), nil
+		}
+		return nil, errors.New("missing key id")
+	}
+
+	key, ok := h.keyring.Get(kid)
+	if !ok {
+		return nil, errors.New("unknown key id")
+	}
+
+	if key.Method.Alg() != t.Method.Alg() {
+		return nil, errors.New("signing method mismatch")
+	}
+
+	return key.PrivateKey.Public(), nil
+}
+
+// acceptsSecretKey tells whether tokens without a kid, signed with the shared
+// JWT_SECRET_KEY, are still valid. That is always the case without a keyring,
+// once a keyring is in use only until JWT_LEGACY_SECRET_UNTIL.
+func (h *jwtUtil) acceptsSecretKey(currentTime time.Time) bool {
+	if h.jwtConfig.SecretKey == "" {
+		return false
+	}
+
+	if h.keyring.Active() == nil {
+		return true
+	}
+
+	return currentTime.Before(h.jwtConfig.LegacySecretUntilTime)
+}
+
+func (h *jwtUtil) JWKS() *JWKSet {
+	return h.keyring.JWKS()
+}
+
+func (h *jwtUtil) ReloadKeys() error {
+	return h.keyring.Load()
+}
@@ -0,0 +1,246 @@
+package jwtutils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyFileExt = ".pem"
+
+type SigningKey struct {
+	KID        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// keyring holds every private key found in the keys directory. The active key
+// signs new tokens, the remaining keys are kept for verification and stay
+// published in the JWKS until their files are removed. A new key is dropped in
+// first and only made active through JWT_ACTIVE_KID once every replica has
+// picked it up.
+type keyring struct {
+	mu          sync.RWMutex
+	dir         string
+	activeKID   string
+	allowedAlgs []string
+	active      *SigningKey
+	keys        map[string]*SigningKey
+}
+
+func newKeyring(dir, activeKID string, allowedAlgs []string) *keyring {
+	return &keyring{
+		dir:         dir,
+		activeKID:   activeKID,
+		allowedAlgs: allowedAlgs,
+		keys:        map[string]*SigningKey{},
+	}
+}
+
+func (k *keyring) Load() error {
+	if k.dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]*SigningKey{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), keyFileExt)
+		key, err := loadSigningKey(filepath.Join(k.dir, entry.Name()), kid)
+		if err != nil {
+			return err
+		}
+
+		// a token signed with an algorithm the parser refuses could never be
+		// verified
+		if !slices.Contains(k.allowedAlgs, key.Method.Alg()) {
+			return fmt.Errorf("signing key %q uses %s which is not in JWT_ALLOWED_ALGS", kid, key.Method.Alg())
+		}
+
+		keys[kid] = key
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("no signing keys found in %s", k.dir)
+	}
+
+	if k.activeKID == "" {
+		return errors.New("no active signing key configured")
+	}
+
+	active, ok := keys[k.activeKID]
+	if !ok {
+		return fmt.Errorf("active signing key %q not found in %s", k.activeKID, k.dir)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = keys
+	k.active = active
+
+	return nil
+}
+
+func (k *keyring) Active() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.active
+}
+
+func (k *keyring) Get(kid string) (*SigningKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func (k *keyring) JWKS() *JWKSet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := &JWKSet{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+func (s *SigningKey) JWK() JWK {
+	jwk := JWK{
+		Kid: s.KID,
+		Use: "sig",
+		Alg: s.Method.Alg(),
+	}
+
+	switch pub := s.PrivateKey.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeSegment(pub.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = encodeSegment(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeSegment(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeSegment(pub)
+	}
+
+	return jwk
+}
+
+func loadSigningKey(path, kid string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM in %s", path)
+	}
+
+	var privateKey any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type in %s", path)
+	}
+
+	method, err := signingMethodFor(signer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &SigningKey{
+		KID:        kid,
+		Method:     method,
+		PrivateKey: signer,
+	}, nil
+}
+
+func signingMethodFor(signer crypto.Signer) (jwt.SigningMethod, error) {
+	switch key := signer.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, errors.New("unsupported signing key")
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
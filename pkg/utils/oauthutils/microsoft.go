@@ -0,0 +1,180 @@
+package oauthutils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/azureadv2"
+	"golang.org/x/oauth2"
+)
+
+const (
+	microsoftAuthURLTemplate  = "https://login.microsoftonline.com/%s/oauth2/v2.0/authorize"
+	microsoftTokenURLTemplate = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+)
+
+// microsoftProvider is the azureadv2 provider keeping the id token of the
+// code exchange, goth drops it and with it the claims that tell whether the
+// email is verified. The profile is still read from Microsoft Graph.
+type microsoftProvider struct {
+	*azureadv2.Provider
+
+	config *oauth2.Config
+	// multiTenant is set when accounts of any tenant can sign in, the email
+	// of such an account is only as trustworthy as its tenant's admin.
+	multiTenant bool
+}
+
+type microsoftSession struct {
+	azureadv2.Session
+
+	IDToken string `json:"it"`
+}
+
+func newMicrosoftProvider(clientID, clientSecret, callbackURL string, opts azureadv2.ProviderOptions) *microsoftProvider {
+	scopes := make([]string, 0, len(opts.Scopes))
+	for _, scope := range opts.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	if len(scopes) == 0 {
+		scopes = []string{string(azureadv2.OpenIDScope), string(azureadv2.ProfileScope), string(azureadv2.EmailScope), string(azureadv2.UserReadScope)}
+	}
+
+	return &microsoftProvider{
+		Provider: azureadv2.New(clientID, clientSecret, callbackURL, opts),
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  callbackURL,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  fmt.Sprintf(microsoftAuthURLTemplate, opts.Tenant),
+				TokenURL: fmt.Sprintf(microsoftTokenURLTemplate, opts.Tenant),
+			},
+			Scopes: scopes,
+		},
+		multiTenant: opts.Tenant == azureadv2.CommonTenant || opts.Tenant == azureadv2.OrganizationsTenant,
+	}
+}
+
+func (p *microsoftProvider) BeginAuth(state string) (goth.Session, error) {
+	return &microsoftSession{
+		Session: azureadv2.Session{
+			AuthURL: p.config.AuthCodeURL(state),
+		},
+	}, nil
+}
+
+func (p *microsoftProvider) UnmarshalSession(data string) (goth.Session, error) {
+	session := &microsoftSession{}
+	err := json.NewDecoder(strings.NewReader(data)).Decode(session)
+	return session, err
+}
+
+// FetchUser adds the claims of the id token to the raw data of the Graph
+// profile. The token came straight from the token endpoint over tls, so its
+// signature does not have to be checked again, only its audience.
+func (p *microsoftProvider) FetchUser(session goth.Session) (goth.User, error) {
+	sess := session.(*microsoftSession)
+
+	user, err := p.Provider.FetchUser(&sess.Session)
+	if err != nil {
+		return user, err
+	}
+
+	claims, err := decodeIDTokenClaims(sess.IDToken)
+	if err != nil {
+		return user, err
+	}
+
+	if claims["aud"] != p.ClientKey {
+		return user, errors.New("audience in id token does not match client id")
+	}
+
+	if user.RawData == nil {
+		user.RawData = map[string]any{}
+	}
+	for key, value := range claims {
+		if _, ok := user.RawData[key]; !ok {
+			user.RawData[key] = value
+		}
+	}
+	user.IDToken = sess.IDToken
+
+	return user, nil
+}
+
+// Authorize exchanges the code like azureadv2 does, but keeps the id token.
+func (s *microsoftSession) Authorize(provider goth.Provider, params goth.Params) (string, error) {
+	p := provider.(*microsoftProvider)
+
+	token, err := p.config.Exchange(goth.ContextForClient(p.Client()), params.Get("code"))
+	if err != nil {
+		return "", err
+	}
+
+	if !token.Valid() {
+		return "", errors.New("invalid token received from provider")
+	}
+
+	s.AccessToken = token.AccessToken
+	s.RefreshToken = token.RefreshToken
+	s.ExpiresAt = token.Expiry
+	s.IDToken, _ = token.Extra("id_token").(string)
+
+	return token.AccessToken, nil
+}
+
+func (s *microsoftSession) Marshal() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (s *microsoftSession) String() string {
+	return s.Marshal()
+}
+
+// isEmailVerified needs email_verified, and for a multi tenant app also
+// xms_edov, the claim that the domain of the email is verified by the
+// tenant owning it. Both are optional claims of the app registration.
+func (p *microsoftProvider) isEmailVerified(user *goth.User) bool {
+	if !isClaimTrue(user.RawData["email_verified"]) {
+		return false
+	}
+
+	return !p.multiTenant || isClaimTrue(user.RawData["xms_edov"])
+}
+
+func decodeIDTokenClaims(idToken string) (map[string]any, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("id token is missing or malformed")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding id token: %w", err)
+	}
+
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("error decoding id token: %w", err)
+	}
+
+	return claims, nil
+}
+
+// isClaimTrue accepts the string form some issuers send booleans in.
+func isClaimTrue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true" || v == "1"
+	default:
+		return false
+	}
+}
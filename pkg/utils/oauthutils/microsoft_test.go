@@ -0,0 +1,54 @@
+package oauthutils
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/azureadv2"
+)
+
+func TestDecodeIDTokenClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"aud":"client","email_verified":true}`))
+
+	claims, err := decodeIDTokenClaims("header." + payload + ".signature")
+	if err != nil {
+		t.Fatalf("decodeIDTokenClaims() error = %v", err)
+	}
+	if claims["aud"] != "client" || claims["email_verified"] != true {
+		t.Fatalf("decodeIDTokenClaims() = %v", claims)
+	}
+
+	for _, token := range []string{"", "header.payload", "header.!!!.signature"} {
+		if _, err := decodeIDTokenClaims(token); err == nil {
+			t.Errorf("decodeIDTokenClaims(%q) expected an error", token)
+		}
+	}
+}
+
+func TestMicrosoftProvider_IsEmailVerified(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant azureadv2.TenantType
+		claims map[string]any
+		want   bool
+	}{
+		{name: "single tenant verified", tenant: "contoso.onmicrosoft.com", claims: map[string]any{"email_verified": true}, want: true},
+		{name: "single tenant unverified", tenant: "contoso.onmicrosoft.com", claims: map[string]any{"email_verified": false}},
+		{name: "single tenant without claim", tenant: "contoso.onmicrosoft.com", claims: map[string]any{}},
+		{name: "multi tenant without domain owner", tenant: azureadv2.CommonTenant, claims: map[string]any{"email_verified": true}},
+		{name: "multi tenant domain owner verified", tenant: azureadv2.OrganizationsTenant, claims: map[string]any{"email_verified": true, "xms_edov": true}, want: true},
+		{name: "multi tenant domain owner as string", tenant: azureadv2.CommonTenant, claims: map[string]any{"email_verified": "true", "xms_edov": "1"}, want: true},
+		{name: "multi tenant domain owner only", tenant: azureadv2.CommonTenant, claims: map[string]any{"xms_edov": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newMicrosoftProvider("client", "secret", "http://localhost/callback", azureadv2.ProviderOptions{Tenant: tt.tenant})
+
+			if got := provider.isEmailVerified(&goth.User{RawData: tt.claims}); got != tt.want {
+				t.Fatalf("isEmailVerified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package oauthutils
+
+import (
+	"fmt"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/azureadv2"
+	"github.com/markbates/goth/providers/github"
+	"github.com/markbates/goth/providers/google"
+	"github.com/markbates/goth/providers/openidConnect"
+)
+
+const (
+	ProviderTypeGoogle    = "google"
+	ProviderTypeGithub    = "github"
+	ProviderTypeMicrosoft = "microsoft"
+	ProviderTypeOIDC      = "oidc"
+)
+
+// UseProviders registers every configured provider with goth. A provider that
+// cannot be built is logged and left out, an unreachable OIDC discovery url
+// should not keep the other logins down.
+func UseProviders(cfg *config.Config) {
+	providers := make([]goth.Provider, 0, len(cfg.Oauth.ProviderNames))
+
+	for _, name := range cfg.Oauth.ProviderNames {
+		provider, err := newProvider(cfg.Oauth.Providers[name], cfg.OIDC.Issuer)
+		if err != nil {
+			logger.Log.Errorf("error registering oauth provider %s: %v", name, err)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	goth.ClearProviders()
+	goth.UseProviders(providers...)
+}
+
+// IsEnabled reports whether logins through the named provider are accepted.
+func IsEnabled(name string) bool {
+	_, err := goth.GetProvider(name)
+	return err == nil
+}
+
+func newProvider(p *config.OauthProvider, baseURL string) (goth.Provider, error) {
+	callbackURL := p.CallbackURL
+	if callbackURL == "" {
+		callbackURL = fmt.Sprintf("%s/oauth/%s/callback", baseURL, p.Name)
+	}
+
+	switch p.Type {
+	case ProviderTypeGoogle:
+		provider := google.New(p.ClientID, p.ClientSecret, callbackURL, p.Scopes...)
+		provider.SetName(p.Name)
+		return provider, nil
+	case ProviderTypeGithub:
+		provider := github.New(p.ClientID, p.ClientSecret, callbackURL, p.Scopes...)
+		provider.SetName(p.Name)
+		return provider, nil
+	case ProviderTypeMicrosoft:
+		opts := azureadv2.ProviderOptions{
+			Tenant: azureadv2.CommonTenant,
+		}
+		if p.Tenant != "" {
+			opts.Tenant = azureadv2.TenantType(p.Tenant)
+		}
+		for _, scope := range p.Scopes {
+			opts.Scopes = append(opts.Scopes, azureadv2.ScopeType(scope))
+		}
+		provider := newMicrosoftProvider(p.ClientID, p.ClientSecret, callbackURL, opts)
+		provider.SetName(p.Name)
+		return provider, nil
+	case ProviderTypeOIDC:
+		if p.DiscoveryURL == "" {
+			return nil, fmt.Errorf("oidc provider %s has no discovery url", p.Name)
+		}
+		provider, err := openidConnect.New(p.ClientID, p.ClientSecret, callbackURL, p.DiscoveryURL, p.Scopes...)
+		if err != nil {
+			return nil, err
+		}
+		provider.SetName(p.Name)
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown oauth provider type %q", p.Type)
+	}
+}
+
+// IsEmailVerified reports whether the provider vouches for the email of the
+// user. A provider that does not say so is not trusted with it.
+func IsEmailVerified(user *goth.User) bool {
+	provider, err := goth.GetProvider(user.Provider)
+	if err != nil {
+		return false
+	}
+
+	switch p := provider.(type) {
+	case *google.Provider:
+		// the v2 userinfo endpoint calls the claim verified_email
+		return isClaimTrue(user.RawData["verified_email"]) || isClaimTrue(user.RawData["email_verified"])
+	case *github.Provider:
+		// github only hands out a public email or the primary one once verified
+		return true
+	case *microsoftProvider:
+		return p.isEmailVerified(user)
+	case *openidConnect.Provider:
+		return isClaimTrue(user.RawData["email_verified"])
+	default:
+		return false
+	}
+}
@@ -66,6 +66,19 @@ func (r *redisUtilLRU) Get(ctx context.Context, key string) (string, error) {
 	return val, nil
 }
 
+func (r *redisUtilLRU) GetDel(ctx context.Context, key string) (string, error) {
+	r.cache.Remove(key)
+
+	val, err := r.client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return val, nil
+}
+
 func (r *redisUtilLRU) GetWithScan(ctx context.Context, key string, dest any) error {
 	err := r.client.Get(ctx, key).Scan(dest)
 	if err == redis.Nil {
@@ -92,3 +105,72 @@ func (r *redisUtilLRU) Delete(ctx context.Context, keys ...string) error {
 
 	return r.client.Del(ctx, keys...).Err()
 }
+
+func (r *redisUtilLRU) SAdd(ctx context.Context, key string, members ...string) error {
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return r.client.SAdd(ctx, key, args...).Err()
+}
+
+func (r *redisUtilLRU) SMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err == redis.Nil {
+		return []string{}, nil
+	}
+	return members, err
+}
+
+func (r *redisUtilLRU) SRem(ctx context.Context, key string, members ...string) error {
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return r.client.SRem(ctx, key, args...).Err()
+}
+
+func (r *redisUtilLRU) Expire(ctx context.Context, key string, duration time.Duration) error {
+	return r.client.Expire(ctx, key, duration).Err()
+}
+
+func (r *redisUtilLRU) Incr(ctx context.Context, key string) (int64, error) {
+	r.cache.Remove(key)
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *redisUtilLRU) IncrWithExpire(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	r.cache.Remove(key)
+	return incrWithExpireScript.Run(ctx, r.client, []string{key}, duration.Milliseconds()).Int64()
+}
+
+func (r *redisUtilLRU) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.client.TTL(ctx, key).Result()
+}
+
+func (r *redisUtilLRU) CompareAndSetJSON(ctx context.Context, key, field, expected string, value any, duration time.Duration) (bool, error) {
+	r.cache.Remove(key)
+	return compareAndSetJSON(ctx, r.client, key, field, expected, value, duration)
+}
+
+func (r *redisUtilLRU) SetWithIndex(ctx context.Context, key string, value any, duration time.Duration, indexKey, member string) error {
+	data, err := setWithIndex(ctx, r.client, key, value, duration, indexKey, member)
+	if err != nil {
+		return err
+	}
+
+	r.cache.Add(key, data)
+	return nil
+}
+
+func (r *redisUtilLRU) DeleteIndex(ctx context.Context, indexKey, keyPrefix string) error {
+	members, err := deleteIndex(ctx, r.client, indexKey, keyPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		r.cache.Remove(keyPrefix + member)
+	}
+	return nil
+}
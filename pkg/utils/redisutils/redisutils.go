@@ -14,11 +14,78 @@ type RedisUtil interface {
 	Set(ctx context.Context, key string, value any, duration time.Duration) error
 	SetJSON(ctx context.Context, key string, value any, duration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	GetDel(ctx context.Context, key string) (string, error)
 	GetWithScan(ctx context.Context, key string, dest any) error
 	GetWithScanJSON(ctx context.Context, key string, dest any) error
 	Delete(ctx context.Context, keys ...string) error
+	SAdd(ctx context.Context, key string, members ...string) error
+	SMembers(ctx context.Context, key string) ([]string, error)
+	SRem(ctx context.Context, key string, members ...string) error
+	Expire(ctx context.Context, key string, duration time.Duration) error
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrWithExpire(ctx context.Context, key string, duration time.Duration) (int64, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	CompareAndSetJSON(ctx context.Context, key, field, expected string, value any, duration time.Duration) (bool, error)
+	SetWithIndex(ctx context.Context, key string, value any, duration time.Duration, indexKey, member string) error
+	DeleteIndex(ctx context.Context, indexKey, keyPrefix string) error
 }
 
+// incrWithExpireScript starts the expiry together with the counter, a crash
+// between the two can then never leave a counter that lives forever.
+var incrWithExpireScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// compareAndSetJSONScript replaces a JSON value only while one of its string
+// fields still holds the expected value, so two writers racing on the same
+// read cannot both win.
+var compareAndSetJSONScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+if cjson.decode(current)[ARGV[1]] ~= ARGV[2] then
+	return 0
+end
+if tonumber(ARGV[4]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[3], 'PX', ARGV[4])
+else
+	redis.call('SET', KEYS[1], ARGV[3])
+end
+return 1
+`)
+
+// setWithIndexScript writes a value and adds it to an index set in one step,
+// so deleting the index never misses a value written at the same time. The
+// index is kept alive at least as long as the value but never shortened.
+var setWithIndexScript = redis.NewScript(`
+if tonumber(ARGV[2]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('SADD', KEYS[2], ARGV[3])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[2]) < tonumber(ARGV[2]) then
+	redis.call('PEXPIRE', KEYS[2], ARGV[2])
+end
+return 1
+`)
+
+// deleteIndexScript deletes an index set together with every key it names,
+// a member added while it runs is either deleted with it or added after.
+var deleteIndexScript = redis.NewScript(`
+local members = redis.call('SMEMBERS', KEYS[1])
+for _, member in ipairs(members) do
+	redis.call('DEL', ARGV[1] .. member)
+end
+redis.call('DEL', KEYS[1])
+return members
+`)
+
 type redisUtil struct {
 	cfg    *config.RedisConfig
 	client *redis.Client
@@ -72,6 +139,16 @@ func (r *redisUtil) Get(ctx context.Context, key string) (string, error) {
 	return val, nil
 }
 
+func (r *redisUtil) GetDel(ctx context.Context, key string) (string, error) {
+	val, err := r.client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
 func (r *redisUtil) GetWithScan(ctx context.Context, key string, dest any) error {
 	err := r.client.Get(ctx, key).Scan(dest)
 	if err == redis.Nil {
@@ -94,3 +171,96 @@ func (r *redisUtil) GetWithScanJSON(ctx context.Context, key string, dest any) e
 func (r *redisUtil) Delete(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
+
+func (r *redisUtil) SAdd(ctx context.Context, key string, members ...string) error {
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return r.client.SAdd(ctx, key, args...).Err()
+}
+
+func (r *redisUtil) SMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err == redis.Nil {
+		return []string{}, nil
+	}
+	return members, err
+}
+
+func (r *redisUtil) SRem(ctx context.Context, key string, members ...string) error {
+	args := make([]any, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return r.client.SRem(ctx, key, args...).Err()
+}
+
+func (r *redisUtil) Expire(ctx context.Context, key string, duration time.Duration) error {
+	return r.client.Expire(ctx, key, duration).Err()
+}
+
+func (r *redisUtil) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *redisUtil) IncrWithExpire(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	return incrWithExpireScript.Run(ctx, r.client, []string{key}, duration.Milliseconds()).Int64()
+}
+
+func (r *redisUtil) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.client.TTL(ctx, key).Result()
+}
+
+func (r *redisUtil) CompareAndSetJSON(ctx context.Context, key, field, expected string, value any, duration time.Duration) (bool, error) {
+	return compareAndSetJSON(ctx, r.client, key, field, expected, value, duration)
+}
+
+func compareAndSetJSON(ctx context.Context, client *redis.Client, key, field, expected string, value any, duration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	swapped, err := compareAndSetJSONScript.Run(ctx, client, []string{key}, field, expected, string(data), duration.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return swapped == 1, nil
+}
+
+func (r *redisUtil) SetWithIndex(ctx context.Context, key string, value any, duration time.Duration, indexKey, member string) error {
+	_, err := setWithIndex(ctx, r.client, key, value, duration, indexKey, member)
+	return err
+}
+
+func (r *redisUtil) DeleteIndex(ctx context.Context, indexKey, keyPrefix string) error {
+	_, err := deleteIndex(ctx, r.client, indexKey, keyPrefix)
+	return err
+}
+
+func setWithIndex(ctx context.Context, client *redis.Client, key string, value any, duration time.Duration, indexKey, member string) (string, error) {
+	data, ok := value.(string)
+	if !ok {
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		data = string(bytes)
+	}
+
+	if err := setWithIndexScript.Run(ctx, client, []string{key, indexKey}, data, duration.Milliseconds(), member).Err(); err != nil {
+		return "", err
+	}
+
+	return data, nil
+}
+
+func deleteIndex(ctx context.Context, client *redis.Client, indexKey, keyPrefix string) ([]string, error) {
+	members, err := deleteIndexScript.Run(ctx, client, []string{indexKey}, keyPrefix).StringSlice()
+	if err == redis.Nil {
+		return []string{}, nil
+	}
+	return members, err
+}
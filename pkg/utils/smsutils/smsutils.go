@@ -0,0 +1,58 @@
+package smsutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+	"github.com/faisalyudiansah/auth-service-template/pkg/logger"
+)
+
+const (
+	SMSSenderLog     = "log"
+	SMSSenderWebhook = "webhook"
+)
+
+type SMSSender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// logSMSSender is the stand-in for a real SMS gateway during local
+// development: only the recipient is logged, messages carry one-time codes,
+// and when a file is configured they are appended to it so they can be read
+// back by hand or by end-to-end tests. The config refuses it in release.
+type logSMSSender struct {
+	filePath string
+	mu       sync.Mutex
+}
+
+func NewLogSMSSender(cfg *config.SMSConfig) *logSMSSender {
+	return &logSMSSender{
+		filePath: cfg.LogFilePath,
+	}
+}
+
+func (s *logSMSSender) Send(ctx context.Context, to, message string) error {
+	logger.Log.WithFields(map[string]any{
+		"to": to,
+	}).Info("sms sent")
+
+	if s.filePath == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), to, message)
+	return err
+}
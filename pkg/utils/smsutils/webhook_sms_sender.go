@@ -0,0 +1,62 @@
+package smsutils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+)
+
+// webhookSMSSender hands messages to an SMS gateway over HTTP, posting
+// {"to": ..., "message": ...} as JSON with the token as bearer credential.
+type webhookSMSSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewWebhookSMSSender(cfg *config.SMSConfig) *webhookSMSSender {
+	return &webhookSMSSender{
+		url:   cfg.WebhookURL,
+		token: cfg.WebhookToken,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (s *webhookSMSSender) Send(ctx context.Context, to, message string) error {
+	body, err := json.Marshal(map[string]string{
+		"to":      to,
+		"message": message,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
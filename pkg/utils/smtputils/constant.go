@@ -8,13 +8,27 @@ import (
 var EmailHTMLTemplates embed.FS
 
 const (
-	ResetPasswordSubject = "authservice - Please reset your password"
-	VerificationSubject  = "authservice - Verify your account"
+	ResetPasswordSubject      = "authservice - Please reset your password"
+	VerificationSubject       = "authservice - Verify your account"
+	MagicLinkSubject          = "authservice - Your sign-in link"
+	PasswordChangedSubject    = "authservice - Your password was changed"
+	EmailChangeConfirmSubject = "authservice - Confirm your new email address"
+	EmailChangeNoticeSubject  = "authservice - Your email address is being changed"
+	ReactivationSubject       = "authservice - Reactivate your account"
+	AccountDeletionSubject    = "authservice - Your account is scheduled for deletion"
+	DataExportSubject         = "authservice - Your data export is ready"
 )
 
 type EmailTemplate string
 
 const (
-	ResetPasswordTemplate EmailTemplate = "templates/forgot-password.html"
-	VerificationTemplate  EmailTemplate = "templates/verification.html"
+	ResetPasswordTemplate      EmailTemplate = "templates/forgot-password.html"
+	VerificationTemplate       EmailTemplate = "templates/verification.html"
+	MagicLinkTemplate          EmailTemplate = "templates/magic-link.html"
+	PasswordChangedTemplate    EmailTemplate = "templates/password-changed.html"
+	EmailChangeConfirmTemplate EmailTemplate = "templates/change-email.html"
+	EmailChangeNoticeTemplate  EmailTemplate = "templates/change-email-notice.html"
+	ReactivationTemplate       EmailTemplate = "templates/reactivation.html"
+	AccountDeletionTemplate    EmailTemplate = "templates/account-deletion.html"
+	DataExportTemplate         EmailTemplate = "templates/data-export.html"
 )
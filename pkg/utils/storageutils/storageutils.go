@@ -0,0 +1,69 @@
+package storageutils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+)
+
+const (
+	StorageBackendLocal = "local"
+	StorageBackendS3    = "s3"
+)
+
+var ErrObjectNotFound = errors.New("storage object not found")
+
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// localStorage keeps objects as files under a directory. The api serves what
+// the worker wrote, so both have to see the same directory, which is why the
+// config refuses it in release.
+type localStorage struct {
+	dir string
+}
+
+func NewLocalStorage(cfg *config.StorageConfig) *localStorage {
+	return &localStorage{
+		dir: cfg.LocalDir,
+	}
+}
+
+// path keeps every key inside the storage directory.
+func (s *localStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.Clean("/"+key))
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, data []byte) error {
+	path := s.path(key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (s *localStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+
+	return data, err
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
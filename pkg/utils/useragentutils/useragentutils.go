@@ -0,0 +1,45 @@
+package useragentutils
+
+import (
+	"github.com/mssola/useragent"
+)
+
+const (
+	DeviceTypeDesktop = "desktop"
+	DeviceTypeMobile  = "mobile"
+	DeviceTypeBot     = "bot"
+	DeviceTypeUnknown = "unknown"
+)
+
+type Device struct {
+	Browser    string
+	OS         string
+	DeviceType string
+}
+
+func Parse(userAgent string) Device {
+	if userAgent == "" {
+		return Device{DeviceType: DeviceTypeUnknown}
+	}
+
+	ua := useragent.New(userAgent)
+
+	browser, version := ua.Browser()
+	if version != "" {
+		browser = browser + " " + version
+	}
+
+	deviceType := DeviceTypeDesktop
+	switch {
+	case ua.Bot():
+		deviceType = DeviceTypeBot
+	case ua.Mobile():
+		deviceType = DeviceTypeMobile
+	}
+
+	return Device{
+		Browser:    browser,
+		OS:         ua.OS(),
+		DeviceType: deviceType,
+	}
+}
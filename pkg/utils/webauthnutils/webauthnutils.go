@@ -0,0 +1,101 @@
+package webauthnutils
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/faisalyudiansah/auth-service-template/pkg/config"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+type WebAuthnUtil interface {
+	BeginRegistration(user *User) (*protocol.CredentialCreation, *webauthn.SessionData, error)
+	FinishRegistration(user *User, session webauthn.SessionData, body []byte) (*webauthn.Credential, error)
+	BeginLogin() (*protocol.CredentialAssertion, *webauthn.SessionData, error)
+	FinishLogin(handler webauthn.DiscoverableUserHandler, session webauthn.SessionData, body []byte) (*webauthn.Credential, error)
+}
+
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+	Credentials []webauthn.Credential
+}
+
+func (u *User) WebAuthnID() []byte {
+	return u.ID
+}
+
+func (u *User) WebAuthnName() string {
+	return u.Name
+}
+
+func (u *User) WebAuthnDisplayName() string {
+	return u.DisplayName
+}
+
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	return u.Credentials
+}
+
+func (u *User) WebAuthnIcon() string {
+	return ""
+}
+
+type webAuthnUtil struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+func NewWebAuthnUtil(cfg *config.WebAuthnConfig) *webAuthnUtil {
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+	})
+	if err != nil {
+		log.Fatalf("error initializing webauthn: %v", err)
+	}
+
+	return &webAuthnUtil{
+		webAuthn: webAuthn,
+	}
+}
+
+func (w *webAuthnUtil) BeginRegistration(user *User) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	return w.webAuthn.BeginRegistration(
+		user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+}
+
+func (w *webAuthnUtil) FinishRegistration(user *User, session webauthn.SessionData, body []byte) (*webauthn.Credential, error) {
+	parsedResponse, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return w.webAuthn.CreateCredential(user, session, parsedResponse)
+}
+
+func (w *webAuthnUtil) BeginLogin() (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	return w.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationPreferred),
+	)
+}
+
+func (w *webAuthnUtil) FinishLogin(handler webauthn.DiscoverableUserHandler, session webauthn.SessionData, body []byte) (*webauthn.Credential, error) {
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return w.webAuthn.ValidateDiscoverableLogin(handler, session, parsedResponse)
+}